@@ -0,0 +1,297 @@
+package webLinks
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"strconv"
+	"strings"
+
+	iana "github.com/conslo/webLinks/rel"
+)
+
+// Title returns the link's title, for display. As RFC 8288, section 3.4.1
+// suggests, title* is preferred over title when both are present, unless its
+// value could not be decoded to UTF-8, in which case title is used instead.
+// A title* that couldn't be decoded is still returned, as it was, if it is the
+// only one. Title returns "" if the link has no title at all.
+func (l Link) Title() string {
+	title, ext := l.titles()
+	switch {
+	case ext != nil && (!ext.undecoded || title == nil):
+		return ext.Value
+	case title != nil:
+		return title.Value
+	}
+	return ""
+}
+
+// titles returns the link's title and title* params, which Params doesn't
+// tell apart, or nil for those it doesn't have.
+func (l Link) titles() (title, ext *Param) {
+	continued := false
+	for i := range l.Ordered {
+		param := &l.Ordered[i]
+		switch {
+		case ext == nil && strings.EqualFold(param.Name, "title*"):
+			ext = &param.Param
+		case title == nil && strings.EqualFold(param.Name, "title"):
+			title = &param.Param
+		default:
+			if base, _, _, ok := section(param.Name); ok && strings.EqualFold(base, "title") {
+				continued = true
+			}
+		}
+	}
+	param, ok := l.Param("title")
+	switch {
+	case !ok:
+	case ext == nil && continued:
+		// Put back together from continuations
+		ext = &param
+	case len(l.Ordered) == 0:
+		// Not parsed, so tell them apart by how they would be written
+		for _, p := range l.values("title") {
+			p := p
+			switch {
+			case ext == nil && p.extended():
+				ext = &p
+			case title == nil && !p.extended():
+				title = &p
+			}
+		}
+	}
+	return title, ext
+}
+
+// Type returns the link's type parameter, the media type of its target as
+// it was given, or "" if it has none. See MediaType to parse it.
+func (l Link) Type() string {
+	param, _ := l.Param("type")
+	return param.Value
+}
+
+// Media returns the link's media parameter, the media its target is intended
+// for, or "" if it has none.
+func (l Link) Media() string {
+	param, _ := l.Param("media")
+	return param.Value
+}
+
+// Hreflang returns every language the link's target is in, as given by its
+// hreflang parameters, which unlike most may appear more than once.
+func (l Link) Hreflang() []string {
+	var langs []string
+	for _, param := range l.Values("hreflang") {
+		langs = append(langs, param.Value)
+	}
+	return langs
+}
+
+// MediaType parses the link's type parameter, the media type of its target,
+// returning the media type, lowercased, along with any of its parameters.
+// MediaType returns "" if the link has no type.
+func (l Link) MediaType() (mediatype string, params map[string]string, err error) {
+	param, ok := l.Param("type")
+	if !ok {
+		return "", nil, nil
+	}
+	return mime.ParseMediaType(param.Value)
+}
+
+// Anchor returns the link's anchor parameter, which makes some other resource
+// than the one the link was found on its context. ok is false if the link has
+// no anchor.
+func (l Link) Anchor() (anchor string, ok bool) {
+	param, ok := l.Param("anchor")
+	return param.Value, ok
+}
+
+// Context returns the IRI of the link's context: the resource the link is
+// from, as given by its anchor, resolved against base. base should be the
+// URL of the resource the link was found on, and is returned as it is for
+// links without an anchor. base may be nil, in which case an anchor that is a
+// relative reference is returned unresolved.
+//
+// Clients that ignore a link's anchor risk attributing it to the wrong
+// resource, as RFC 8288, section 3.2 warns.
+func (l Link) Context(base *url.URL) (*url.URL, error) {
+	anchor, ok := l.Anchor()
+	if !ok {
+		return base, nil
+	}
+	ref, err := url.Parse(anchor)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		return ref, nil
+	}
+	return base.ResolveReference(ref), nil
+}
+
+// HasRel reports whether rel is one of the link's relation types. The rel
+// param may hold several, separated by whitespace, like rel="next last".
+// Registered relation types are compared case-insensitively, as RFC 8288,
+// section 2.1.1 requires, while extension ones, being URIs, are compared as
+// they are.
+func (l Link) HasRel(rel string) bool {
+	for _, r := range l.relTypes() {
+		if sameRel(r, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rev returns each of the relation types in the link's rev parameter, which
+// RFC 8288 deprecates but is still seen in the wild. A rev states how the
+// link's context relates to its target, the reverse of what rel does, and
+// has no bearing on the link's rel.
+func (l Link) Rev() []string {
+	rev, ok := l.Param("rev")
+	if !ok {
+		return nil
+	}
+	return strings.Fields(rev.Value)
+}
+
+// inverseRels maps the well-known rev values to the relation types that
+// mean the same thing the other way around.
+var inverseRels = map[string]string{
+	"alternate":           "alternate",
+	"canonical":           "shortlink",
+	"collection":          "item",
+	"item":                "collection",
+	"made":                "author",
+	"next":                "prev",
+	"prev":                "next",
+	"previous":            "next",
+	"next-archive":        "prev-archive",
+	"prev-archive":        "next-archive",
+	"predecessor-version": "successor-version",
+	"successor-version":   "predecessor-version",
+}
+
+// RevAsRel translates the link's well-known rev values into the relation
+// types that are their inverse, which might be added to its rel. A link with
+// rev=canonical, for instance, is a shortlink for its context. Any other rev
+// values are left out.
+func (l Link) RevAsRel() []string {
+	var rels []string
+	for _, rev := range l.Rev() {
+		if rel, ok := inverseRels[strings.ToLower(rev)]; ok && !contains(rels, rel) {
+			rels = append(rels, rel)
+		}
+	}
+	return rels
+}
+
+// ValidRel reports whether rel, the value of a rel parameter, is a valid list
+// of relation types. Each must either look like a registered type, such as
+// "next", or be an absolute URI, such as "http://example.com/rel/author",
+// and they must be separated by whitespace.
+func ValidRel(rel string) bool {
+	types := strings.Fields(rel)
+	for _, t := range types {
+		if !isRelType(t) {
+			return false
+		}
+	}
+	return len(types) > 0
+}
+
+// CheckRel checks rel, the value of a rel parameter, as ValidRel does, and
+// also that each of the bare tokens it holds is a relation type registered
+// with IANA, as RFC 8288, section 2.1.1 requires of them, catching typos like
+// "nextt". Extension relation types, being absolute URIs, needn't be
+// registered. CheckRel returns an error wrapping ErrBadRel if rel isn't a
+// list of relation types, or ErrUnregisteredRel naming those that aren't
+// registered.
+func CheckRel(rel string) error {
+	if !ValidRel(rel) {
+		return fmt.Errorf("%w: %q is not a list of relation types", ErrBadRel, rel)
+	}
+	var unregistered []string
+	for _, t := range strings.Fields(rel) {
+		if isRegisteredRel(t) && !iana.Registered(t) {
+			unregistered = append(unregistered, strconv.Quote(t))
+		}
+	}
+	if unregistered != nil {
+		return fmt.Errorf("%w: %s", ErrUnregisteredRel, strings.Join(unregistered, ", "))
+	}
+	return nil
+}
+
+// checkAttr reports any problem with the value of the param called name,
+// found between start and end, that is specific to the attribute it sets.
+func (p *parser) checkAttr(name string, value Param, start, end int) error {
+	switch strings.ToLower(name) {
+	case "rel", "rev":
+		if !ValidRel(value.Value) {
+			return p.fail(WarnBadRel, start, end, "parameter %q is not a list of relation types", name)
+		}
+		if p.RegisteredRels {
+			if err := CheckRel(value.Value); err != nil {
+				return p.fail(WarnUnregisteredRel, start, end, "parameter %q holds relation types that aren't registered", name)
+			}
+		}
+	case "anchor":
+		if !isURIReference(value.Value) {
+			return p.fail(WarnBadValue, start, end, "parameter %q is not a URI-reference", name)
+		}
+	case "type":
+		if _, _, err := mime.ParseMediaType(value.Value); err != nil {
+			return p.fail(WarnBadMediaType, start, end, "parameter %q is not a media type: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// TargetAttributes holds the standard parameters of a link, as described in
+// RFC 8288, section 3.4.1, along with its rel and anchor.
+type TargetAttributes struct {
+	Rel      []string
+	Anchor   string
+	Hreflang []string // every occurrence, as hreflang may appear more than once
+	Media    string
+	Title    string
+	// TitleStar is the decoded value of title*, the title in a charset and
+	// language of its own. See Link.Title for the one to display.
+	TitleStar string
+	Type      string
+	// Extensions holds the link's other params, by name.
+	Extensions map[string]Param
+}
+
+// standardAttrs are the names of the params kept out of
+// TargetAttributes.Extensions.
+var standardAttrs = []string{"rel", "anchor", "hreflang", "media", "title", "type"}
+
+// Attributes returns a typed view of the link's standard parameters. Only the
+// first occurrence of rel, anchor, media, title, title* and type counts, as
+// RFC 8288 requires.
+func (l Link) Attributes() TargetAttributes {
+	attrs := TargetAttributes{
+		Rel:        l.relTypes(),
+		Extensions: map[string]Param{},
+	}
+	attrs.Anchor, _ = l.Anchor()
+	attrs.Hreflang = l.Hreflang()
+	attrs.Media = l.Media()
+	attrs.Type = l.Type()
+	title, ext := l.titles()
+	if title != nil {
+		attrs.Title = title.Value
+	}
+	if ext != nil {
+		attrs.TitleStar = ext.Value
+	}
+	for name, param := range l.Params {
+		if !contains(standardAttrs, strings.ToLower(name)) {
+			attrs.Extensions[name] = param
+		}
+	}
+	return attrs
+}
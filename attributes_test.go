@@ -0,0 +1,328 @@
+package webLinks_test
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+var titleTests = []struct {
+	input string
+	title string
+}{
+	{`</a>; rel=next`, ""},
+	{`</a>; rel=next; title="Next"`, "Next"},
+	{`</a>; rel=next; title*=UTF-8'en'N%C3%A4chste`, "Nächste"},
+	// title* wins, wherever it appears
+	{`</a>; rel=next; title="Next"; title*=UTF-8'de'N%C3%A4chste`, "Nächste"},
+	{`</a>; rel=next; title*=UTF-8'de'N%C3%A4chste; title="Next"`, "Nächste"},
+	{`</a>; rel=next; Title="Next"; TITLE*=UTF-8'de'N%C3%A4chste`, "Nächste"},
+	// Unless it can't be decoded
+	{`</a>; rel=next; title="Next"; title*=UTF-8'de'N%C3%A4chste%`, "Next"},
+	{`</a>; rel=next; title*=x-unknown'de'N%E4chste; title="Next"`, "Next"},
+	{`</a>; rel=next; title="Next"; title*=UTF-8'de'N%E4chste`, "Next"},
+	// Still better than nothing
+	{`</a>; rel=next; title*=UTF-8'de'N%C3%A4chste%`, "N%C3%A4chste%"},
+	// Continuations
+	{`</a>; rel=next; title="Next"; title*0*=UTF-8'de'N%C3%A4ch; title*1*=ste`, "Nächste"},
+	{`</a>; rel=next; title*0="Ne"; title*1="xt"`, "Next"},
+}
+
+func TestLinkTitle(t *testing.T) {
+	t.Parallel()
+	for _, test := range titleTests {
+		if title := webLinks.Parse(test.input)[0].Title(); title != test.title {
+			t.Fatalf("Got %q expected %q for %q\n", title, test.title, test.input)
+		}
+	}
+}
+
+func TestLinkTitleUnparsed(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Link{
+		URI:    "/a",
+		Params: map[string]webLinks.Param{"title": {Value: "Next"}},
+	}
+	if title := link.Title(); title != "Next" {
+		t.Fatalf("Got %q expected %q\n", title, "Next")
+	}
+}
+
+var mediaTypeTests = []struct {
+	input     string
+	mediatype string
+	params    map[string]string
+}{
+	{`</a>; rel=alternate`, "", nil},
+	{`</a>; rel=alternate; type=text/html`, "text/html", map[string]string{}},
+	{`</a>; rel=alternate; type="Text/HTML; charset=UTF-8"`, "text/html", map[string]string{"charset": "UTF-8"}},
+	{`</a>; rel=alternate; type="application/atom+xml; type=feed"`, "application/atom+xml", map[string]string{"type": "feed"}},
+}
+
+func TestLinkMediaType(t *testing.T) {
+	t.Parallel()
+	for _, test := range mediaTypeTests {
+		mediatype, params, err := webLinks.Parse(test.input)[0].MediaType()
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v\n", test.input, err)
+		}
+		if mediatype != test.mediatype || len(params) != len(test.params) {
+			t.Fatalf("Got %q %v expected %q %v\n", mediatype, params, test.mediatype, test.params)
+		}
+		for k, v := range test.params {
+			if params[k] != v {
+				t.Fatalf("Got %q expected %q for %q\n", params[k], v, k)
+			}
+		}
+	}
+}
+
+func TestParseBadMediaType(t *testing.T) {
+	t.Parallel()
+	const input = `</a>; rel=alternate; type="text/"`
+	if _, err := webLinks.ParseStrict(input); !errors.Is(err, webLinks.ErrBadMediaType) {
+		t.Fatalf("Expected %v, got %v\n", webLinks.ErrBadMediaType, err)
+	}
+	links, warnings := webLinks.ParseLenient(input)
+	if len(warnings) != 1 || warnings[0].Kind != webLinks.WarnBadMediaType {
+		t.Fatalf("Expected a warning, got %v\n", warnings)
+	}
+	if _, _, err := links[0].MediaType(); err == nil {
+		t.Fatalf("Expected an error\n")
+	}
+}
+
+var relTests = []struct {
+	rel   string
+	valid bool
+}{
+	{"next", true},
+	{"next prev", true},
+	{" next\tprev ", true},
+	{"Next", true},
+	{"pre.load-2", true},
+	{"http://example.com/rel/author", true},
+	{"tag:example.com,2024:rel", true},
+	{"", false},
+	{"  ", false},
+	{"/rel/author", false},
+	{"../author", false},
+	{"2nd", false},
+	{"next,prev", false},
+	{"next http://example.com/a b\"c", false},
+}
+
+func TestValidRel(t *testing.T) {
+	t.Parallel()
+	for _, test := range relTests {
+		if valid := webLinks.ValidRel(test.rel); valid != test.valid {
+			t.Fatalf("Got %v expected %v for %q\n", valid, test.valid, test.rel)
+		}
+	}
+}
+
+func TestParseBadRel(t *testing.T) {
+	t.Parallel()
+	const input = `</a>; rel="next /rel/author"`
+	if _, err := webLinks.ParseStrict(input); !errors.Is(err, webLinks.ErrBadRel) {
+		t.Fatalf("Expected %v, got %v\n", webLinks.ErrBadRel, err)
+	}
+	links, warnings := webLinks.ParseLenient(input)
+	if len(warnings) != 1 || warnings[0].Kind != webLinks.WarnBadRel {
+		t.Fatalf("Expected a warning, got %v\n", warnings)
+	}
+	if rel := links[0].Params["rel"].Value; rel != "next /rel/author" {
+		t.Fatalf("Expected the rel to be kept, got %q\n", rel)
+	}
+}
+
+var contextTests = []struct {
+	input   string
+	base    string
+	context string
+}{
+	{`</a>; rel=next`, "http://example.com/page", "http://example.com/page"},
+	{`</a>; rel=next; anchor="#section"`, "http://example.com/page", "http://example.com/page#section"},
+	{`</a>; rel=next; anchor="/other"`, "http://example.com/dir/page", "http://example.com/other"},
+	{`</a>; rel=next; anchor="sibling"`, "http://example.com/dir/page", "http://example.com/dir/sibling"},
+	{`</a>; rel=next; anchor="https://example.org/x"`, "http://example.com/page", "https://example.org/x"},
+	{`</a>; rel=next; anchor="/other"`, "", "/other"},
+}
+
+func TestLinkContext(t *testing.T) {
+	t.Parallel()
+	for _, test := range contextTests {
+		var base *url.URL
+		if test.base != "" {
+			base, _ = url.Parse(test.base)
+		}
+		context, err := webLinks.Parse(test.input)[0].Context(base)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v\n", test.input, err)
+		}
+		if context.String() != test.context {
+			t.Fatalf("Got %q expected %q for %q\n", context, test.context, test.input)
+		}
+	}
+}
+
+func TestLinkAnchor(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; rel=next; anchor="#foo"; anchor="#bar"`)[0]
+	if anchor, ok := link.Anchor(); !ok || anchor != "#foo" {
+		t.Fatalf("Got %q %v expected %q\n", anchor, ok, "#foo")
+	}
+	if _, ok := webLinks.Parse(`</a>; rel=next`)[0].Anchor(); ok {
+		t.Fatalf("Expected no anchor\n")
+	}
+	if _, err := webLinks.ParseStrict(`</a>; rel=next; anchor="a b"`); !errors.Is(err, webLinks.ErrBadValue) {
+		t.Fatalf("Expected %v, got %v\n", webLinks.ErrBadValue, err)
+	}
+}
+
+var revTests = []struct {
+	input string
+	rev   []string
+	rels  []string
+}{
+	{`</a>; rel=next`, nil, nil},
+	{`<http://sho.rt/x>; rev=canonical`, []string{"canonical"}, []string{"shortlink"}},
+	{`</a>; rel=up; rev="next made"`, []string{"next", "made"}, []string{"prev", "author"}},
+	{`</a>; rev="Prev previous bookmark"`, []string{"Prev", "previous", "bookmark"}, []string{"next"}},
+}
+
+func TestLinkRev(t *testing.T) {
+	t.Parallel()
+	for _, test := range revTests {
+		link := webLinks.Parse(test.input)[0]
+		if rev := link.Rev(); strings.Join(rev, " ") != strings.Join(test.rev, " ") {
+			t.Fatalf("Got %q expected %q for %q\n", rev, test.rev, test.input)
+		}
+		if rels := link.RevAsRel(); strings.Join(rels, " ") != strings.Join(test.rels, " ") {
+			t.Fatalf("Got %q expected %q for %q\n", rels, test.rels, test.input)
+		}
+	}
+}
+
+func TestLinkRevNotRel(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`<http://sho.rt/x>; rev=canonical`)
+	if _, ok := links[0].Params["rel"]; ok {
+		t.Fatalf("Expected rev to be kept apart from rel, got %+v\n", links[0].Params)
+	}
+	if len(links.Map()) != 0 {
+		t.Fatalf("Expected no relations, got %+v\n", links.Map())
+	}
+}
+
+func TestLinkAttributes(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; rel="alternate next"; anchor="#x"; hreflang=en; hreflang=de; media=print; media=screen; ` +
+		`title="Next"; title*=UTF-8'de'N%C3%A4chste; type="text/html"; foo=bar; ext*=UTF-8''%C3%A4`)[0]
+	attrs := link.Attributes()
+	if strings.Join(attrs.Rel, " ") != "alternate next" {
+		t.Fatalf("Got rel %q\n", attrs.Rel)
+	}
+	if strings.Join(attrs.Hreflang, " ") != "en de" {
+		t.Fatalf("Got hreflang %q\n", attrs.Hreflang)
+	}
+	if attrs.Anchor != "#x" || attrs.Media != "print" || attrs.Type != "text/html" {
+		t.Fatalf("Got %+v\n", attrs)
+	}
+	if attrs.Title != "Next" || attrs.TitleStar != "Nächste" {
+		t.Fatalf("Got title %q and title* %q\n", attrs.Title, attrs.TitleStar)
+	}
+	if len(attrs.Extensions) != 2 || attrs.Extensions["foo"].Value != "bar" || attrs.Extensions["ext"].Value != "ä" {
+		t.Fatalf("Got extensions %+v\n", attrs.Extensions)
+	}
+
+	attrs = webLinks.Link{URI: "/a", Params: map[string]webLinks.Param{"title": {Value: "Next"}}}.Attributes()
+	if attrs.Title != "Next" || attrs.TitleStar != "" || attrs.Rel != nil {
+		t.Fatalf("Got %+v\n", attrs)
+	}
+}
+
+var hasRelTests = []struct {
+	input string
+	rel   string
+	has   bool
+}{
+	{`</a>; rel=next`, "next", true},
+	{`</a>; rel="next last"`, "next", true},
+	{`</a>; rel="next last"`, "last", true},
+	{`</a>; rel="next	last"`, "last", true},
+	{`</a>; rel=Next`, "next", true},
+	{`</a>; rel=next`, "NEXT", true},
+	{`</a>; rel="nextpage"`, "next", false},
+	{`</a>; rel=prev`, "next", false},
+	{`</a>; title=next`, "next", false},
+	{`</a>; rel="http://example.com/rel/Page"`, "http://example.com/rel/Page", true},
+	{`</a>; rel="http://example.com/rel/Page"`, "http://example.com/rel/page", false},
+}
+
+func TestLinkHasRel(t *testing.T) {
+	t.Parallel()
+	for _, test := range hasRelTests {
+		if has := webLinks.Parse(test.input)[0].HasRel(test.rel); has != test.has {
+			t.Fatalf("Got %v expected %v for %q in %q\n", has, test.has, test.rel, test.input)
+		}
+	}
+}
+
+func TestLinkGetters(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; rel=alternate; type="text/html"; type="text/plain"; media=print; media=screen; hreflang=en; hreflang=de`)[0]
+	if link.Type() != "text/html" || link.Media() != "print" {
+		t.Fatalf("Got type %q and media %q\n", link.Type(), link.Media())
+	}
+	if hreflang := link.Hreflang(); strings.Join(hreflang, " ") != "en de" {
+		t.Fatalf("Got %q\n", hreflang)
+	}
+
+	link = webLinks.Parse(`</a>; rel=next`)[0]
+	if link.Type() != "" || link.Media() != "" || link.Hreflang() != nil {
+		t.Fatalf("Got %q %q %q\n", link.Type(), link.Media(), link.Hreflang())
+	}
+}
+
+var checkRelTests = []struct {
+	rel string
+	err error
+}{
+	{"next", nil},
+	{"Next LAST", nil},
+	{"describedby http://example.com/rel/custom", nil},
+	{"nextt", webLinks.ErrUnregisteredRel},
+	{"next foo", webLinks.ErrUnregisteredRel},
+	{"", webLinks.ErrBadRel},
+	{"next /relative", webLinks.ErrBadRel},
+}
+
+func TestCheckRel(t *testing.T) {
+	t.Parallel()
+	for _, test := range checkRelTests {
+		if err := webLinks.CheckRel(test.rel); !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+			t.Fatalf("Expected %v for %q, got %v\n", test.err, test.rel, err)
+		}
+	}
+}
+
+func TestParseRegisteredRels(t *testing.T) {
+	t.Parallel()
+	const input = `</a>; rel=nextt, </b>; rel=next, </c>; rel="http://example.com/rel/custom"; rev=canonical`
+	p := webLinks.NewParser(webLinks.WithRegisteredRels())
+	links, warnings := p.ParseLenient(input)
+	if len(links) != 3 || len(warnings) != 1 || warnings[0].Kind != webLinks.WarnUnregisteredRel {
+		t.Fatalf("Expected one warning, got %+v %v\n", links, warnings)
+	}
+	p.Strict = true
+	if _, err := p.Parse(input); !errors.Is(err, webLinks.ErrUnregisteredRel) {
+		t.Fatalf("Expected ErrUnregisteredRel, got %v\n", err)
+	}
+	if _, warnings := webLinks.ParseLenient(input); len(warnings) != 0 {
+		t.Fatalf("Expected unregistered rels to be accepted by default, got %v\n", warnings)
+	}
+}
@@ -0,0 +1,300 @@
+package webLinks
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NewLink returns a link to uri with the given params, in order, once it has
+// checked that it is valid, as Links.Build does. Characters that may never
+// appear unencoded in a URI, like spaces and non-ASCII ones, are percent
+// encoded in uri first. Params named more than once are all kept, in
+// Duplicates. Params that don't state a charset are taken to be US-ASCII, or
+// UTF-8 if they hold anything else.
+func NewLink(uri string, params ...LinkParam) (Link, error) {
+	l := Link{URI: escapeURI(uri), Params: map[string]Param{}}
+	for _, param := range params {
+		if param.Enc == "" && !param.Flag {
+			param.Enc, param.Lang = "us-ascii", "en-us"
+			if !isASCII(param.Value) {
+				param.Enc, param.Lang = "UTF-8", ""
+			}
+		}
+		l.Ordered = append(l.Ordered, param)
+		if prev, ok := l.Params[param.Name]; ok {
+			if l.Duplicates == nil {
+				l.Duplicates = map[string][]Param{}
+			}
+			if _, ok := l.Duplicates[param.Name]; !ok {
+				l.Duplicates[param.Name] = []Param{prev}
+			}
+			l.Duplicates[param.Name] = append(l.Duplicates[param.Name], param.Param)
+		}
+		if _, ok := l.Params[param.Name]; !ok || !firstWins(param.Name) {
+			l.Params[param.Name] = param.Param
+		}
+	}
+	if err := l.validate(); err != nil {
+		return Link{}, err
+	}
+	return l, nil
+}
+
+// LinkBuilder builds up a Link, one parameter at a time, for formatting into
+// a Link header. Its methods return the builder, so that calls can be chained:
+//
+//	BuildLink("https://api.example.com/items?page=2").Rel("next").Title("Page 2").Link()
+type LinkBuilder struct {
+	link Link
+}
+
+// BuildLink starts building a link to uri. Characters that may never appear
+// unencoded in a URI, like spaces and non-ASCII ones, are percent encoded, so
+// that BuildLink("/search?q=café") links to "/search?q=caf%C3%A9".
+func BuildLink(uri string) *LinkBuilder {
+	return &LinkBuilder{link: Link{URI: escapeURI(uri), Params: map[string]Param{}}}
+}
+
+// Rel sets the link's relation types.
+func (b *LinkBuilder) Rel(rels ...string) *LinkBuilder {
+	return b.Param("rel", strings.Join(rels, " "))
+}
+
+// Anchor sets the link's anchor, making some other resource its context.
+func (b *LinkBuilder) Anchor(anchor string) *LinkBuilder {
+	return b.Param("anchor", anchor)
+}
+
+// Title sets the link's title. Titles that aren't printable US-ASCII are
+// written as a title* instead, as RFC 8187 describes. See TitleWithFallback
+// to also write a plain title for clients that don't understand those.
+func (b *LinkBuilder) Title(title string) *LinkBuilder {
+	return b.Param("title", title)
+}
+
+// TitleLang sets the link's title, stating its language, so it is always
+// written as a title*.
+func (b *LinkBuilder) TitleLang(title, lang string) *LinkBuilder {
+	b.Param("title", title)
+	b.link.Params["title"] = Param{Value: title, Enc: "UTF-8", Lang: lang}
+	return b
+}
+
+// TitleWithFallback sets the link's title as Title does, but if it is written
+// as a title* also writes a plain title before it, folded to US-ASCII as well
+// as can be: accents are stripped, and anything else that isn't printable
+// US-ASCII is replaced by '?'. Clients that don't understand title* can
+// display that instead, while others prefer the title*, as RFC 8288, section
+// 3.4.1 has them do.
+func (b *LinkBuilder) TitleWithFallback(title string) *LinkBuilder {
+	b.Title(title)
+	ext := b.link.Params["title"]
+	if !ext.extended() {
+		return b
+	}
+	fallback := Param{Value: asciiFallback(title), Enc: "us-ascii", Lang: "en-us"}
+	b.link.Params["title"] = fallback
+	if b.link.Duplicates == nil {
+		b.link.Duplicates = map[string][]Param{}
+	}
+	b.link.Duplicates["title"] = []Param{fallback, ext}
+	return b
+}
+
+// asciiFallback folds s to printable US-ASCII, stripping accents and
+// replacing whatever else it can't fold by '?'.
+func asciiFallback(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// A combining accent, dropped
+		case r == '\t' || r >= ' ' && r < 0x7f:
+			b.WriteRune(r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// Type sets the media type of the link's target.
+func (b *LinkBuilder) Type(mediatype string) *LinkBuilder {
+	return b.Param("type", mediatype)
+}
+
+// Media sets the media the link's target is intended for.
+func (b *LinkBuilder) Media(media string) *LinkBuilder {
+	return b.Param("media", media)
+}
+
+// As sets the kind of resource the target of a rel=preload link is, such as
+// "script", "style" or "font".
+func (b *LinkBuilder) As(destination string) *LinkBuilder {
+	return b.Param("as", destination)
+}
+
+// CrossOrigin sets the CORS mode the link's target is to be fetched in,
+// "anonymous" or "use-credentials". "anonymous", being what a crossorigin
+// param means without a value, is written as one.
+func (b *LinkBuilder) CrossOrigin(mode string) *LinkBuilder {
+	if strings.EqualFold(mode, "anonymous") {
+		return b.Flag("crossorigin")
+	}
+	return b.Param("crossorigin", mode)
+}
+
+// Integrity sets the hashes the link's target must match, as subresource
+// integrity metadata like "sha384-...".
+func (b *LinkBuilder) Integrity(hashes ...string) *LinkBuilder {
+	return b.Param("integrity", strings.Join(hashes, " "))
+}
+
+// NoPush asks servers that push the targets of rel=preload links not to push
+// the link's.
+func (b *LinkBuilder) NoPush() *LinkBuilder {
+	return b.Flag("nopush")
+}
+
+// FetchPriority sets how the link's target is to be prioritized against
+// others of its kind: "high", "low" or "auto".
+func (b *LinkBuilder) FetchPriority(priority string) *LinkBuilder {
+	return b.Param("fetchpriority", priority)
+}
+
+// Hreflang adds a language the link's target is in. It may be called more
+// than once, for targets in several languages.
+func (b *LinkBuilder) Hreflang(lang string) *LinkBuilder {
+	prev, ok := b.link.Params["hreflang"]
+	langs := b.link.Duplicates["hreflang"]
+	b.Param("hreflang", lang)
+	if ok {
+		if b.link.Duplicates == nil {
+			b.link.Duplicates = map[string][]Param{}
+		}
+		if langs == nil {
+			langs = []Param{prev}
+		}
+		b.link.Duplicates["hreflang"] = append(langs, b.link.Params["hreflang"])
+	}
+	return b
+}
+
+// Param sets the param called name, replacing any previous values. Values
+// that aren't printable US-ASCII are written as ext-values, with a "*"
+// appended to name.
+func (b *LinkBuilder) Param(name, value string) *LinkBuilder {
+	param := Param{Value: value, Enc: "us-ascii", Lang: "en-us"}
+	if !isASCII(value) {
+		param.Enc, param.Lang = "UTF-8", ""
+	}
+	b.link.Params[name] = param
+	delete(b.link.Duplicates, name)
+	return b
+}
+
+// Flag sets a param called name which has no value at all.
+func (b *LinkBuilder) Flag(name string) *LinkBuilder {
+	b.link.Params[name] = Param{Flag: true}
+	delete(b.link.Duplicates, name)
+	return b
+}
+
+// Link returns the link built so far.
+func (b *LinkBuilder) Link() Link {
+	return b.link
+}
+
+// Build returns the link built so far once it has checked that it is valid,
+// as Links.Build does.
+func (b *LinkBuilder) Build() (Link, error) {
+	if err := b.link.validate(); err != nil {
+		return Link{}, err
+	}
+	return b.link, nil
+}
+
+// String formats the link built so far, as Link.String does.
+func (b *LinkBuilder) String() string {
+	return b.link.String()
+}
+
+// Build formats the links as a Link header field value, as String does, once
+// it has checked that each of them is valid: that its target is a
+// URI-reference once escaped, its params are named by tokens and hold valid
+// UTF-8, and its rel, anchor and type have valid values. The errors returned wrap the Err*
+// variables describing the problem. Build doesn't check that relation types
+// are registered; see CheckRel for that.
+func (l Links) Build() (string, error) {
+	var errs []error
+	for _, link := range l {
+		if err := link.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return "", err
+	}
+	return l.String(), nil
+}
+
+// validate checks that the link can be formatted into a valid Link header.
+func (l Link) validate() error {
+	// Whatever needs escaping is escaped when formatted
+	if hasBadEscape(l.URI) {
+		return fmt.Errorf("%w: %q is not a URI-reference", ErrBadTarget, l.URI)
+	}
+	if _, err := url.Parse(escapeURI(l.URI)); err != nil {
+		return fmt.Errorf("%w: %v", ErrBadTarget, err)
+	}
+	rel, ok := l.Param("rel")
+	if !ok {
+		return fmt.Errorf("%w: link to %q has no rel parameter", ErrMissingRel, l.URI)
+	}
+	if !ValidRel(rel.Value) {
+		return fmt.Errorf("%w: %q is not a list of relation types", ErrBadRel, rel.Value)
+	}
+	for name, param := range l.Params {
+		if !isToken(name) || strings.HasSuffix(name, "*") {
+			return fmt.Errorf("%w: %q is not a token", ErrBadParamName, name)
+		}
+		if err := param.validUTF8(name); err != nil {
+			return err
+		}
+		switch strings.ToLower(name) {
+		case "anchor":
+			if !isURIReference(param.Value) {
+				return fmt.Errorf("%w: anchor %q is not a URI-reference", ErrBadValue, param.Value)
+			}
+		case "type":
+			if _, _, err := mime.ParseMediaType(param.Value); err != nil {
+				return fmt.Errorf("%w: %q: %v", ErrBadMediaType, param.Value, err)
+			}
+		}
+	}
+	for name, params := range l.Duplicates {
+		for _, param := range params {
+			if err := param.validUTF8(name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validUTF8 checks that the value of the param called name is valid UTF-8,
+// as it is written as such whenever it isn't US-ASCII. Values that couldn't
+// be decoded when parsed are written as they appeared, so aren't checked.
+func (p Param) validUTF8(name string) error {
+	if !p.undecoded && !utf8.ValidString(p.Value) {
+		return fmt.Errorf("%w: value of %q is not valid UTF-8", ErrBadValue, name)
+	}
+	return nil
+}
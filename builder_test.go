@@ -0,0 +1,192 @@
+package webLinks_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestBuildLink(t *testing.T) {
+	t.Parallel()
+	link := webLinks.BuildLink("https://api.example.com/items?page=2").
+		Rel("next").
+		Title("Page 2").
+		Param("foo", "bar").
+		Link()
+	const expected = `<https://api.example.com/items?page=2>; rel=next; foo=bar; title="Page 2"`
+	if link.String() != expected {
+		t.Fatalf("Got %s expected %s\n", link, expected)
+	}
+	if link.Params["rel"].Value != "next" || link.Title() != "Page 2" {
+		t.Fatalf("Got %+v\n", link)
+	}
+}
+
+var builderTests = []struct {
+	builder *webLinks.LinkBuilder
+	output  string
+}{
+	{webLinks.BuildLink("/a").Rel("next", "last"), `</a>; rel="next last"`},
+	{webLinks.BuildLink("/a").Rel("next").Title(`Say "hi"`), `</a>; rel=next; title="Say \"hi\""`},
+	{webLinks.BuildLink("/a").Rel("next").Title("Nächste"), `</a>; rel=next; title*=UTF-8''N%C3%A4chste`},
+	{webLinks.BuildLink("/a").Rel("next").TitleLang("Next", "en"), `</a>; rel=next; title*=UTF-8'en'Next`},
+	{webLinks.BuildLink("/a").Rel("alternate").Hreflang("de").Hreflang("en"), `</a>; rel=alternate; hreflang=de; hreflang=en`},
+	{webLinks.BuildLink("/a").Rel("alternate").Type("text/html").Media("print").Anchor("#x"), `</a>; rel=alternate; anchor=#x; media=print; type="text/html"`},
+	{webLinks.BuildLink("/a").Rel("next").Param("foo", "a").Param("foo", "b"), `</a>; rel=next; foo=b`},
+	{webLinks.BuildLink("/a").Rel("next").Flag("foo"), `</a>; rel=next; foo`},
+	{webLinks.BuildLink("/a").Rel("alternate").Hreflang("de").Hreflang("en").Hreflang("fr"), `</a>; rel=alternate; hreflang=de; hreflang=en; hreflang=fr`},
+	{webLinks.BuildLink("/a").Rel("next").Title("Line\nbreak"), `</a>; rel=next; title*=UTF-8''Line%0Abreak`},
+	{webLinks.BuildLink("/a").Rel("next").Title("Tab\there"), "</a>; rel=next; title=\"Tab\there\""},
+	{webLinks.BuildLink("/a").Rel("next").TitleWithFallback("Nächstes Kapitel"), `</a>; rel=next; title="Nachstes Kapitel"; title*=UTF-8''N%C3%A4chstes%20Kapitel`},
+	{webLinks.BuildLink("/a").Rel("next").TitleWithFallback("次へ"), `</a>; rel=next; title="??"; title*=UTF-8''%E6%AC%A1%E3%81%B8`},
+	{webLinks.BuildLink("/a").Rel("next").TitleWithFallback("Next"), `</a>; rel=next; title=Next`},
+	{webLinks.BuildLink("/a").Rel("next").TitleWithFallback("Nächste").Title("Next"), `</a>; rel=next; title=Next`},
+}
+
+func TestTitleWithFallback(t *testing.T) {
+	t.Parallel()
+	link := webLinks.BuildLink("/a").Rel("next").TitleWithFallback("Nächste").Link()
+	if link.Title() != "Nächste" {
+		t.Fatalf("Expected the title* to be preferred, got %q\n", link.Title())
+	}
+	if attrs := link.Attributes(); attrs.Title != "Nachste" || attrs.TitleStar != "Nächste" {
+		t.Fatalf("Got %+v\n", attrs)
+	}
+	if reparsed := webLinks.Parse(link.String())[0]; reparsed.Title() != "Nächste" || reparsed.Attributes().Title != "Nachste" {
+		t.Fatalf("Got %+v\n", reparsed)
+	}
+}
+
+func TestLinkBuilder(t *testing.T) {
+	t.Parallel()
+	for _, test := range builderTests {
+		if output := test.builder.String(); output != test.output {
+			t.Fatalf("Got %s expected %s\n", output, test.output)
+		}
+		if links := webLinks.Parse(test.output); links.String() != test.output {
+			t.Fatalf("Got %s back from %s\n", links, test.output)
+		}
+	}
+}
+
+func TestLinksBuild(t *testing.T) {
+	t.Parallel()
+	header, err := webLinks.Links{
+		webLinks.BuildLink("/items?page=1").Rel("prev").Link(),
+		webLinks.BuildLink("/items?page=3").Rel("next").Link(),
+	}.Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if expected := `</items?page=1>; rel=prev, </items?page=3>; rel=next`; header != expected {
+		t.Fatalf("Got %s expected %s\n", header, expected)
+	}
+}
+
+var badBuildTests = []struct {
+	link webLinks.Link
+	err  error
+}{
+	{webLinks.BuildLink("/100%").Rel("next").Link(), webLinks.ErrBadTarget},
+	{webLinks.BuildLink("/%zz").Rel("next").Link(), webLinks.ErrBadTarget},
+	{webLinks.BuildLink("http://[::1/").Rel("next").Link(), webLinks.ErrBadTarget},
+	{webLinks.BuildLink("/a").Title("No rel").Link(), webLinks.ErrMissingRel},
+	{webLinks.BuildLink("/a").Rel("/relative").Link(), webLinks.ErrBadRel},
+	{webLinks.BuildLink("/a").Rel("next").Param("a b", "c").Link(), webLinks.ErrBadParamName},
+	{webLinks.BuildLink("/a").Rel("next").Param("title*", "c").Link(), webLinks.ErrBadParamName},
+	{webLinks.BuildLink("/a").Rel("next").Anchor("a b").Link(), webLinks.ErrBadValue},
+	{webLinks.BuildLink("/a").Rel("next").Title("\xff\xfe").Link(), webLinks.ErrBadValue},
+	{webLinks.BuildLink("/a").Rel("next").Type("text/").Link(), webLinks.ErrBadMediaType},
+}
+
+func TestLinksBuildErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range badBuildTests {
+		header, err := webLinks.Links{test.link}.Build()
+		if !errors.Is(err, test.err) {
+			t.Fatalf("Expected %v for %s, got %v\n", test.err, test.link, err)
+		}
+		if header != "" {
+			t.Fatalf("Expected no header, got %s\n", header)
+		}
+	}
+}
+
+func TestNewLink(t *testing.T) {
+	t.Parallel()
+	link, err := webLinks.NewLink("/a",
+		webLinks.LinkParam{Name: "rel", Param: webLinks.Param{Value: "next"}},
+		webLinks.LinkParam{Name: "title", Param: webLinks.Param{Value: "Nächste"}},
+		webLinks.LinkParam{Name: "hreflang", Param: webLinks.Param{Value: "de"}},
+		webLinks.LinkParam{Name: "hreflang", Param: webLinks.Param{Value: "en"}},
+		webLinks.LinkParam{Name: "rel", Param: webLinks.Param{Value: "ignored"}},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if link.Params["rel"].Value != "next" || len(link.Ordered) != 5 || len(link.Values("hreflang")) != 2 {
+		t.Fatalf("Got %+v\n", link)
+	}
+	if title := link.Params["title"]; title.Enc != "UTF-8" || title.Value != "Nächste" {
+		t.Fatalf("Got %+v\n", title)
+	}
+	if rel := link.Params["rel"]; rel.Enc != "us-ascii" || rel.Lang != "en-us" {
+		t.Fatalf("Got %+v\n", rel)
+	}
+}
+
+func TestNewLinkErrors(t *testing.T) {
+	t.Parallel()
+	rel := webLinks.LinkParam{Name: "rel", Param: webLinks.Param{Value: "next"}}
+	for _, test := range []struct {
+		uri    string
+		params []webLinks.LinkParam
+		err    error
+	}{
+		{"/100%", []webLinks.LinkParam{rel}, webLinks.ErrBadTarget},
+		{"/a", nil, webLinks.ErrMissingRel},
+		{"/a", []webLinks.LinkParam{{Name: "rel", Param: webLinks.Param{Value: "next,prev"}}}, webLinks.ErrBadRel},
+		{"/a", []webLinks.LinkParam{rel, {Name: "bad name", Param: webLinks.Param{Value: "x"}}}, webLinks.ErrBadParamName},
+	} {
+		link, err := webLinks.NewLink(test.uri, test.params...)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("Expected %v for %q, got %v\n", test.err, test.uri, err)
+		}
+		if link.URI != "" || link.Params != nil {
+			t.Fatalf("Expected no link, got %+v\n", link)
+		}
+	}
+}
+
+var escapeTests = []struct {
+	uri      string
+	expected string
+}{
+	{"/search?q=café", "/search?q=caf%C3%A9"},
+	{"/a b", "/a%20b"},
+	{`/<x>"{|}\^` + "`", "/%3Cx%3E%22%7B%7C%7D%5C%5E%60"},
+	{"/already%20encoded", "/already%20encoded"},
+	{"https://example.com/ünïcode#frag", "https://example.com/%C3%BCn%C3%AFcode#frag"},
+}
+
+func TestBuildLinkEscapes(t *testing.T) {
+	t.Parallel()
+	for _, test := range escapeTests {
+		link, err := webLinks.BuildLink(test.uri).Rel("next").Build()
+		if err != nil || link.URI != test.expected {
+			t.Fatalf("Got %q, %v expected %q\n", link.URI, err, test.expected)
+		}
+		if link, err := webLinks.NewLink(test.uri, webLinks.LinkParam{Name: "rel", Param: webLinks.Param{Value: "next"}}); err != nil || link.URI != test.expected {
+			t.Fatalf("Got %q, %v expected %q\n", link.URI, err, test.expected)
+		}
+		// Links that weren't built are escaped when formatted
+		header, err := webLinks.Links{{URI: test.uri, Params: map[string]webLinks.Param{"rel": {Value: "next"}}}}.Build()
+		if expected := "<" + test.expected + ">; rel=next"; err != nil || header != expected {
+			t.Fatalf("Got %q, %v expected %q\n", header, err, expected)
+		}
+	}
+	if _, err := webLinks.BuildLink("/100%").Rel("next").Build(); !errors.Is(err, webLinks.ErrBadTarget) {
+		t.Fatalf("Expected ErrBadTarget, got %v\n", err)
+	}
+}
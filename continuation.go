@@ -0,0 +1,143 @@
+package webLinks
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// continuation is one section of a param split up as described in RFC 2231,
+// section 3, as found between start and end.
+type continuation struct {
+	Param
+	n          int
+	encoded    bool
+	start, end int
+}
+
+// valueStart returns the offset within header at which the section's value
+// starts.
+func (c continuation) valueStart(header string) int {
+	param := header[c.start:c.end]
+	value := strings.TrimLeft(param[strings.IndexByte(param, '=')+1:], " \t")
+	return c.end - len(value)
+}
+
+// section splits the name of an RFC 2231 continuation, like "title*1" or
+// "title*1*", into the name of the param it is a section of and its number.
+// encoded is set for the sections that are percent encoded. ok is false for
+// names that aren't those of a continuation.
+func section(name string) (base string, n int, encoded, ok bool) {
+	if strings.HasSuffix(name, "*") {
+		encoded = true
+		name = name[:len(name)-1]
+	}
+	star := strings.LastIndexByte(name, '*')
+	if star < 1 {
+		return "", 0, false, false
+	}
+	digits := name[star+1:]
+	if digits == "" || !onlyChars(digits, "0123456789") || len(digits) > 1 && digits[0] == '0' {
+		// Section numbers can't have leading zeros
+		return "", 0, false, false
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return "", 0, false, false
+	}
+	return name[:star], n, encoded, true
+}
+
+// reassemble puts the sections of the param called base back together,
+// returning the name it should be stored under along with its value.
+//
+// Sections are ordered by number, wherever they appeared. Encoded sections
+// are percent decoded, and the whole value then transcoded from the charset
+// given by the first section, if that one is encoded.
+func (p *parser) reassemble(base string, sections []continuation) (string, Param, error) {
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sections[i].n < sections[j].n
+	})
+	first := sections[0]
+	// Where the sections span, which needn't be from first, if they were
+	// out of order
+	start, end := first.start, first.end
+	for _, s := range sections {
+		if s.start < start {
+			start = s.start
+		}
+		if s.end > end {
+			end = s.end
+		}
+	}
+
+	name := base
+	if first.encoded {
+		name += "*"
+	}
+	param := Param{
+		Enc:  "us-ascii",
+		Lang: "en-us",
+	}
+
+	var value, raw strings.Builder
+	want := 0
+	for _, s := range sections {
+		switch {
+		case s.n < want:
+			if err := p.fail(WarnBadContinuation, s.start, s.end, "section %d of parameter %q appears more than once", s.n, base); err != nil {
+				return "", Param{}, err
+			}
+			continue
+		case s.n > want:
+			if err := p.fail(WarnBadContinuation, start, end, "parameter %q is missing section %d", base, want); err != nil {
+				return "", Param{}, err
+			}
+		}
+		want = s.n + 1
+		raw.WriteString(s.Raw)
+
+		v := s.Value
+		if !s.encoded {
+			value.WriteString(v)
+			continue
+		}
+		if s.n == 0 {
+			if parts := strings.SplitN(v, "'", 3); len(parts) == 3 {
+				param.Enc, param.Lang, v = parts[0], parts[1], parts[2]
+				if err := p.checkMeta(base, param.Enc, param.Lang, s.valueStart(p.s)); err != nil {
+					return "", Param{}, err
+				}
+			}
+		}
+		decoded, ok := pctDecode(v)
+		if !ok {
+			param.undecoded = true
+			if err := p.fail(WarnBadExtValue, s.start, s.end, "section %d of parameter %q is not properly encoded", s.n, base); err != nil {
+				return "", Param{}, err
+			}
+		}
+		value.WriteString(decoded)
+	}
+
+	param.Value = value.String()
+	param.Raw = raw.String()
+	if first.encoded {
+		if !knownCharset(param.Enc) {
+			param.undecoded = true
+		} else if transcoded, err := toUTF8(param.Enc, param.Value); err == nil {
+			param.Value = transcoded
+		} else {
+			param.undecoded = true
+			if err := p.fail(WarnBadExtValue, start, end, "parameter %q is not valid %s", base, param.Enc); err != nil {
+				return "", Param{}, err
+			}
+		}
+	}
+	if p.NFC {
+		param.Value = norm.NFC.String(param.Value)
+	}
+	return name, param, nil
+}
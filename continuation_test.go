@@ -0,0 +1,105 @@
+package webLinks_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+var continuationTests = []struct {
+	input string
+	value string
+	enc   string
+	lang  string
+}{
+	{`</a>; rel=next; title*0="Part one, "; title*1="part two"`, "Part one, part two", "us-ascii", "en-us"},
+	{`</a>; rel=next; title*0=one; title*1=two`, "onetwo", "us-ascii", "en-us"},
+	// Encoded and unencoded sections mixed
+	{`</a>; rel=next; title*0*=UTF-8'fr'%C3%A9t%C3%A9%20; title*1="et "; title*2*=hiver`, "été et hiver", "UTF-8", "fr"},
+	{`</a>; rel=next; title*0*=iso-8859-1'de'n%E4ch; title*1*=stes`, "nächstes", "iso-8859-1", "de"},
+	// Ordered by number, not as they appear nor as strings
+	{`</a>; rel=next; title*2=c; title*10=k; title*0=a; title*1=b; title*3=d; title*4=e; title*5=f; title*6=g; title*7=h; title*8=i; title*9=j`, "abcdefghijk", "us-ascii", "en-us"},
+	// Only one section, still a continuation
+	{`</a>; rel=next; title*0="alone"`, "alone", "us-ascii", "en-us"},
+}
+
+func TestParseContinuations(t *testing.T) {
+	t.Parallel()
+	for _, test := range continuationTests {
+		links, warnings := webLinks.ParseLenient(test.input)
+		if len(warnings) != 0 {
+			t.Fatalf("Unexpected warnings for %q: %v\n", test.input, warnings)
+		}
+		param, ok := links[0].Params["title"]
+		if !ok {
+			t.Fatalf("No title for %q, got %+v\n", test.input, links[0].Params)
+		}
+		if param.Value != test.value || param.Enc != test.enc || param.Lang != test.lang {
+			t.Fatalf("Value mismatch for %q, got %+v expected %+v\n", test.input, param, test)
+		}
+	}
+}
+
+func TestParseContinuationsOrdered(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; rel=next; title*1="two"; title*0*=UTF-8''one%20`)[0]
+	expected := []string{"rel", "title*1", "title*0*"}
+	if len(link.Ordered) != len(expected) {
+		t.Fatalf("Length mismatch, got %d expected %d\n", len(link.Ordered), len(expected))
+	}
+	for i, param := range link.Ordered {
+		if param.Name != expected[i] {
+			t.Fatalf("Name mismatch at %d, got %q expected %q\n", i, param.Name, expected[i])
+		}
+	}
+	if link.Ordered[2].Value != "UTF-8''one%20" {
+		t.Fatalf("Expected the section to be left undecoded, got %q\n", link.Ordered[2].Value)
+	}
+	if title := link.Params["title"]; title.Value != "one two" || title.Raw != "UTF-8''one%20\"two\"" {
+		t.Fatalf("Got %+v\n", title)
+	}
+}
+
+var badContinuationTests = []struct {
+	input string
+	value string
+	err   error
+}{
+	{`</a>; rel=next; title*0=a; title*2=c`, "ac", webLinks.ErrBadContinuation},
+	{`</a>; rel=next; title*1=b`, "b", webLinks.ErrBadContinuation},
+	{`</a>; rel=next; title*0=a; title*0=b; title*1=c`, "ac", webLinks.ErrBadContinuation},
+	{`</a>; rel=next; title*0*=UTF-8''a; title*1*=b'c`, "ab'c", webLinks.ErrBadExtValue},
+	{`</a>; rel=next; title*0*=UTF-8''a; title*1*=%G0`, "a%G0", webLinks.ErrBadExtValue},
+	{`</a>; rel=next; title*2=b; title*0=a`, "ab", webLinks.ErrBadContinuation},
+	{`</a>; rel=next; title*2*=UTF-8''b; title*0*=UTF-8''a`, "aUTF-8''b", webLinks.ErrBadExtValue},
+}
+
+func TestParseBadContinuations(t *testing.T) {
+	t.Parallel()
+	for _, test := range badContinuationTests {
+		if _, err := webLinks.ParseStrict(test.input); !errors.Is(err, test.err) {
+			t.Fatalf("Expected %v for %q, got %v\n", test.err, test.input, err)
+		}
+		links, warnings := webLinks.ParseLenient(test.input)
+		if len(warnings) == 0 {
+			t.Fatalf("Expected warnings for %q\n", test.input)
+		}
+		if title := links[0].Params["title"].Value; title != test.value {
+			t.Fatalf("Got %q expected %q for %q\n", title, test.value, test.input)
+		}
+	}
+}
+
+func TestParseNotContinuations(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; rel=next; title*01=a; *0=b; title*x=c`)[0]
+	for _, name := range []string{"title*01", "*0", "title*x"} {
+		if _, ok := link.Params[name]; !ok {
+			t.Fatalf("Expected %q to be kept as is, got %+v\n", name, link.Params)
+		}
+	}
+	if _, ok := link.Params["title"]; ok {
+		t.Fatalf("Unexpected title %+v\n", link.Params["title"])
+	}
+}
@@ -0,0 +1,95 @@
+package webLinks
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/language"
+)
+
+// pctDecode decodes the pct-encoded octets of an ext-value, as described in
+// RFC 8187. Unlike url.QueryUnescape, anything other than a "%" followed by
+// two hex digits is left alone, "+" included. ok is false if s holds a "%"
+// that isn't followed by two hex digits.
+func pctDecode(s string) (decoded string, ok bool) {
+	i := strings.IndexByte(s, '%')
+	if i == -1 {
+		return s, true
+	}
+	orig := s
+	out := make([]byte, 0, len(s))
+	for ; i != -1; i = strings.IndexByte(s, '%') {
+		if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			return orig, false
+		}
+		out = append(out, s[:i]...)
+		out = append(out, unhex(s[i+1])<<4|unhex(s[i+2]))
+		s = s[i+3:]
+	}
+	return string(append(out, s...)), true
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+// charset returns the encoding called name, or nil if it isn't known.
+func charset(name string) encoding.Encoding {
+	switch strings.ToLower(name) {
+	case "utf-8", "us-ascii":
+		// Nothing to do
+		return encoding.Nop
+	}
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil {
+		return nil
+	}
+	return enc
+}
+
+// knownCharset reports whether values encoded in charset can be transcoded to
+// UTF-8.
+func knownCharset(name string) bool {
+	return charset(name) != nil
+}
+
+// errNotUTF8 is returned by toUTF8 for values that claim to be UTF-8, or
+// ASCII, but aren't.
+var errNotUTF8 = errors.New("invalid UTF-8")
+
+// toUTF8 transcodes s from the charset called name to UTF-8. Values in
+// unknown charsets are returned as they are.
+func toUTF8(name, s string) (string, error) {
+	enc := charset(name)
+	switch {
+	case enc == nil:
+		return s, nil
+	case enc == encoding.Nop:
+		if !utf8.ValidString(s) {
+			return s, errNotUTF8
+		}
+		return s, nil
+	}
+	return enc.NewDecoder().String(s)
+}
+
+// Language parses the param's language into a BCP 47 tag, for matching
+// against a user's preferences with a language.Matcher. Params that aren't
+// extended have the "en-us" default, while extended ones that leave their
+// language empty get language.Und.
+func (p Param) Language() (language.Tag, error) {
+	if p.Lang == "" {
+		return language.Und, nil
+	}
+	return language.Parse(p.Lang)
+}
@@ -0,0 +1,146 @@
+package webLinks_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+var extValueTests = []struct {
+	input string
+	value string
+	enc   string
+	lang  string
+}{
+	{`</a>; rel=next; title*=UTF-8'en'1+1%3D2`, "1+1=2", "UTF-8", "en"},
+	{`</a>; rel=next; title*=UTF-8''%E2%82%AC%20rates`, "€ rates", "UTF-8", ""},
+	{`</a>; rel=next; title*=utf-8'de'%c3%a4`, "ä", "utf-8", "de"},
+	{`</a>; rel=next; title*=UTF-8'en'50%25`, "50%", "UTF-8", "en"},
+	// Malformed, left as it was
+	{`</a>; rel=next; title*=UTF-8'en'100%`, "100%", "UTF-8", "en"},
+	{`</a>; rel=next; title*=UTF-8'en'%41%4`, "%41%4", "UTF-8", "en"},
+}
+
+func TestParseExtValues(t *testing.T) {
+	t.Parallel()
+	for _, test := range extValueTests {
+		param := webLinks.Parse(test.input)[0].Params["title"]
+		if param.Value != test.value || param.Enc != test.enc || param.Lang != test.lang {
+			t.Fatalf("Value mismatch for %q, got %+v expected %+v\n", test.input, param, test)
+		}
+	}
+}
+
+var charsetTests = []struct {
+	input string
+	value string
+}{
+	{`</a>; rel=next; title*=iso-8859-1'de'n%E4chstes`, "nächstes"},
+	{`</a>; rel=next; title*=ISO-8859-1'fr'%E9t%E9`, "été"},
+	{`</a>; rel=next; title*=KOI8-R'ru'%F3%D4%D2%C1%CE%C9%C3%C1`, "Страница"},
+	{`</a>; rel=next; title*=windows-1252'en'%80%20rates`, "€ rates"},
+	// Unknown, left as it was
+	{`</a>; rel=next; title*=x-unknown'en'%E4`, "\xe4"},
+}
+
+func TestParseCharsets(t *testing.T) {
+	t.Parallel()
+	for _, test := range charsetTests {
+		param := webLinks.Parse(test.input)[0].Params["title"]
+		if param.Value != test.value {
+			t.Fatalf("Value mismatch for %q, got %q expected %q\n", test.input, param.Value, test.value)
+		}
+	}
+}
+
+func TestParserUTF8Only(t *testing.T) {
+	t.Parallel()
+	const input = `</a>; rel=next; title*=iso-8859-1'de'n%E4chstes, </b>; rel=prev; title*=utf-8'de'n%C3%A4chstes`
+
+	links, err := webLinks.ParseStrict(input)
+	if !errors.Is(err, webLinks.ErrNotUTF8) {
+		t.Fatalf("Expected ErrNotUTF8, got %v\n", err)
+	}
+	if len(links) != 1 || links[0].URI != "/b" {
+		t.Fatalf("Expected only the UTF-8 link, got %v\n", links)
+	}
+
+	p := webLinks.Parser{UTF8Only: true}
+	links, warnings := p.ParseLenient(input)
+	if len(links) != 2 || links[0].Params["title"].Value != "nächstes" {
+		t.Fatalf("Expected both links to be decoded, got %v\n", links)
+	}
+	if len(warnings) != 1 || warnings[0].Kind != webLinks.WarnNotUTF8 {
+		t.Fatalf("Expected a warning for the ISO-8859-1 value, got %v\n", warnings)
+	}
+
+	if _, warnings := webLinks.ParseLenient(input); len(warnings) != 0 {
+		t.Fatalf("Unexpected warnings %v\n", warnings)
+	}
+}
+
+func TestParserNFC(t *testing.T) {
+	t.Parallel()
+	// "été" with decomposed accents, as an ext-value and as a quoted-string
+	const input = "</a>; rel=next; title*=UTF-8'fr'e%CC%81te%CC%81, </b>; rel=prev; title=\"e\u0301te\u0301\""
+
+	p := webLinks.Parser{NFC: true}
+	links, err := p.Parse(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	for _, link := range links {
+		if title := link.Params["title"].Value; title != "\u00e9t\u00e9" {
+			t.Fatalf("Got %q expected %q\n", title, "\u00e9t\u00e9")
+		}
+	}
+
+	if title := webLinks.Parse(input)[1].Params["title"].Value; title != "e\u0301te\u0301" {
+		t.Fatalf("Expected the value to be left alone by default, got %q\n", title)
+	}
+}
+
+var languageTests = []struct {
+	input string
+	tag   string
+}{
+	{`</a>; rel=next; title*=UTF-8'de-CH'Gr%C3%BCezi`, "de-CH"},
+	{`</a>; rel=next; title*=UTF-8'EN-gb'Hello`, "en-GB"},
+	{`</a>; rel=next; title*=UTF-8''Hello`, "und"},
+	{`</a>; rel=next; title="Hello"`, "en-US"},
+	{`</a>; rel=next; title*0*=UTF-8'fr'Bon; title*1=jour`, "fr"},
+}
+
+func TestParamLanguage(t *testing.T) {
+	t.Parallel()
+	for _, test := range languageTests {
+		tag, err := webLinks.Parse(test.input)[0].Params["title"].Language()
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v\n", test.input, err)
+		}
+		if tag.String() != test.tag {
+			t.Fatalf("Got %q expected %q for %q\n", tag, test.tag, test.input)
+		}
+	}
+}
+
+func TestParseBadLanguage(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{
+		`</a>; rel=next; title*=UTF-8'en-'Hello`,
+		`</a>; rel=next; title*=UTF-8'toolongsubtag'Hello`,
+		`</a>; rel=next; title*0*=UTF-8'-en'Hel; title*1=lo`,
+	} {
+		if _, err := webLinks.ParseStrict(input); !errors.Is(err, webLinks.ErrBadLanguage) {
+			t.Fatalf("Expected %v for %q, got %v\n", webLinks.ErrBadLanguage, input, err)
+		}
+		links, warnings := webLinks.ParseLenient(input)
+		if len(warnings) != 1 || warnings[0].Kind != webLinks.WarnBadLanguage {
+			t.Fatalf("Expected a warning for %q, got %v\n", input, warnings)
+		}
+		if _, err := links[0].Params["title"].Language(); err == nil {
+			t.Fatalf("Expected an error for %q\n", input)
+		}
+	}
+}
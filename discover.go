@@ -0,0 +1,174 @@
+package webLinks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+// maxDiscoveryBody is how much of an HTML document Discover reads, looking
+// for links.
+const maxDiscoveryBody = 1 << 20
+
+// Discover fetches the resource at target with a GET request made with ctx,
+// using client, or http.DefaultClient if client is nil, and returns its
+// links: those of the response's "Link" header, followed by those ParseHTML
+// finds in its body, if it is HTML. This is the discovery that Webmention,
+// WebSub and IndieAuth clients do, preferring the header's links to the
+// document's. The set's context is the URL the resource was fetched from,
+// after any redirects, and every link's target is resolved against it.
+//
+// Only the first megabyte of a document is read. Responses without a 2xx
+// status are an error.
+func Discover(ctx context.Context, client *http.Client, target string) (LinkSet, error) {
+	base, header, doc, err := discover(ctx, client, target)
+	if err != nil {
+		return LinkSet{}, err
+	}
+	links := header
+	for _, link := range doc {
+		link.Index = len(links)
+		links = append(links, link)
+	}
+	return NewLinkSet(base, links), nil
+}
+
+// discover is Discover, keeping the links of the header and of the document
+// apart, for protocols that only look in the document when the header lacks
+// what they look for.
+func discover(ctx context.Context, client *http.Client, target string) (base *url.URL, header, doc Links, err error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	req.Header.Set("Accept", "text/html, application/xhtml+xml;q=0.9, */*;q=0.1")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, nil, nil, fmt.Errorf("webLinks: fetching %s: %s", target, resp.Status)
+	}
+	// Targets that can't be resolved are left as they were
+	header, _ = FromResponse(resp)
+	base = resp.Request.URL
+	if mediatype, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediatype == "text/html" || mediatype == "application/xhtml+xml" {
+		doc, err = ParseHTML(io.LimitReader(resp.Body, maxDiscoveryBody), base)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return base, header, doc, nil
+}
+
+// Endpoint returns the target of the set's first link with the relation
+// type rel, resolved against its context, as discovery protocols take their
+// endpoints from. It returns an error wrapping ErrNotFound if there is no
+// such link.
+func (s LinkSet) Endpoint(rel string) (*url.URL, error) {
+	link, ok := s.Links.First(rel)
+	if !ok {
+		return nil, fmt.Errorf("%w: no %s link for %s", ErrNotFound, rel, s.Context)
+	}
+	return s.TargetOf(link)
+}
+
+// DiscoverWebmention returns the Webmention endpoint of the page at target,
+// as the Webmention specification has senders discover it: the first
+// rel=webmention link in the response's "Link" header, or failing that, in
+// its HTML, found as Discover finds them. An empty href makes the page its
+// own endpoint. Query strings in the endpoint are kept, and must be when
+// sending it Webmentions.
+func DiscoverWebmention(ctx context.Context, client *http.Client, target string) (*url.URL, error) {
+	set, err := Discover(ctx, client, target)
+	if err != nil {
+		return nil, err
+	}
+	return set.Endpoint("webmention")
+}
+
+// WebSub holds what a subscriber discovers about a topic, as described in
+// the WebSub specification, section 4.
+type WebSub struct {
+	Hubs []*url.URL // the hubs to subscribe to the topic at, in order
+	Self *url.URL   // the topic's own URL, to subscribe to it by
+}
+
+// DiscoverWebSub fetches the topic at target, as Discover does, and returns
+// its rel=hub and rel=self links. As WebSub requires, the links of the
+// response's "Link" header are used if it has any hubs, and only otherwise
+// those of its HTML. A topic without a self link is taken to be at the URL
+// it was fetched from, after any redirects. It returns an error wrapping
+// ErrNotFound if the topic has no hub.
+func DiscoverWebSub(ctx context.Context, client *http.Client, target string) (WebSub, error) {
+	base, header, doc, err := discover(ctx, client, target)
+	if err != nil {
+		return WebSub{}, err
+	}
+	links := header
+	if _, ok := header.First("hub"); !ok {
+		links = doc
+	}
+	set := NewLinkSet(base, links)
+	var sub WebSub
+	for _, link := range links.Rel("hub") {
+		if hub, err := set.TargetOf(link); err == nil {
+			sub.Hubs = append(sub.Hubs, hub)
+		}
+	}
+	if sub.Hubs == nil {
+		return WebSub{}, fmt.Errorf("%w: no hub link for %s", ErrNotFound, base)
+	}
+	if sub.Self, err = set.Endpoint("self"); err != nil {
+		sub.Self = base
+	}
+	return sub, nil
+}
+
+// IndieAuth holds what a client discovers about a user from their profile
+// URL, as described in the IndieAuth specification, section 4.1, along with
+// the rel=me links RelMeAuth verifies identities by. Endpoints that aren't
+// found are nil.
+type IndieAuth struct {
+	// Metadata is the server's metadata document, of rel=indieauth-metadata,
+	// which clients should prefer to the endpoints given separately.
+	Metadata              *url.URL
+	AuthorizationEndpoint *url.URL
+	TokenEndpoint         *url.URL
+	// Me holds the user's other profiles, of rel=me, in order, without
+	// duplicates.
+	Me []*url.URL
+}
+
+// DiscoverIndieAuth fetches the profile at target, as Discover does, and
+// returns the IndieAuth endpoints and rel=me links it has. Links in the
+// response's "Link" header win over those in its HTML. The URL the profile
+// was fetched from, after any redirects, is the user's canonical profile URL,
+// and is returned along with them.
+func DiscoverIndieAuth(ctx context.Context, client *http.Client, target string) (IndieAuth, *url.URL, error) {
+	set, err := Discover(ctx, client, target)
+	if err != nil {
+		return IndieAuth{}, nil, err
+	}
+	var auth IndieAuth
+	auth.Metadata, _ = set.Endpoint("indieauth-metadata")
+	auth.AuthorizationEndpoint, _ = set.Endpoint("authorization_endpoint")
+	auth.TokenEndpoint, _ = set.Endpoint("token_endpoint")
+	seen := map[string]bool{}
+	for _, link := range set.Links.Rel("me") {
+		me, err := set.TargetOf(link)
+		if err != nil || seen[me.String()] {
+			continue
+		}
+		seen[me.String()] = true
+		auth.Me = append(auth.Me, me)
+	}
+	return auth, set.Context, nil
+}
@@ -0,0 +1,155 @@
+package webLinks_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+// discoveryServer serves each of pages with the "Link" header and HTML given
+// for its path, redirecting from /redirect to /page.
+func discoveryServer(t *testing.T, pages map[string][2]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/page", http.StatusFound)
+			return
+		}
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if page[0] != "" {
+			w.Header().Set("Link", page[0])
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page[1])
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+	srv := discoveryServer(t, map[string][2]string{
+		"/page": {`</from-header>; rel=webmention`, `<link rel=webmention href="/from-html"><a rel=author href=/me>`},
+	})
+	set, err := webLinks.Discover(context.Background(), srv.Client(), srv.URL+"/redirect")
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	if set.Context.String() != srv.URL+"/page" {
+		t.Fatalf("Expected the context to be where it was redirected, got %v\n", set.Context)
+	}
+	if len(set.Links) != 3 || set.Links[0].URI != srv.URL+"/from-header" || set.Links[2].URI != srv.URL+"/me" || set.Links[2].Index != 2 {
+		t.Fatalf("Got %v\n", set.Links)
+	}
+	if _, err := webLinks.Discover(context.Background(), srv.Client(), srv.URL+"/missing"); err == nil {
+		t.Fatalf("Expected an error for a missing page\n")
+	}
+}
+
+var webmentionTests = []struct {
+	header, html string
+	want         string
+}{
+	{`</endpoint?x=1>; rel="webmention"`, `<link rel=webmention href=/other>`, "/endpoint?x=1"},
+	{`<https://webmention.example/ep>; rel=WebMention`, ``, "https://webmention.example/ep"},
+	{`</a>; rel=other`, `<a rel="nofollow webmention" href="relative">x</a><link rel=webmention href=/later>`, "/relative"},
+	{``, `<link rel=webmention href="">`, "/page"},
+}
+
+func TestDiscoverWebmention(t *testing.T) {
+	t.Parallel()
+	for _, test := range webmentionTests {
+		srv := discoveryServer(t, map[string][2]string{"/page": {test.header, test.html}})
+		endpoint, err := webLinks.DiscoverWebmention(context.Background(), srv.Client(), srv.URL+"/redirect")
+		if err != nil {
+			t.Fatalf("Unexpected error %v for %q\n", err, test.html)
+		}
+		want := test.want
+		if want[0] == '/' {
+			want = srv.URL + want
+		}
+		if endpoint.String() != want {
+			t.Fatalf("Got %v expected %s\n", endpoint, want)
+		}
+	}
+	srv := discoveryServer(t, map[string][2]string{"/page": {`</a>; rel=next`, `<p>nothing`}})
+	if _, err := webLinks.DiscoverWebmention(context.Background(), srv.Client(), srv.URL+"/page"); !errors.Is(err, webLinks.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v\n", err)
+	}
+}
+
+func TestDiscoverWebSub(t *testing.T) {
+	t.Parallel()
+	srv := discoveryServer(t, map[string][2]string{
+		"/page":  {`<https://hub.example.com/>; rel=hub, </feed>; rel=self, <https://hub2.example.com/>; rel="hub"`, `<link rel=hub href=https://ignored.example.com/>`},
+		"/html":  {`</feed>; rel=self`, `<link rel=hub href="https://hub.example.com/"><link rel=self href="/topic">`},
+		"/plain": {``, `<p>nothing`},
+	})
+	sub, err := webLinks.DiscoverWebSub(context.Background(), srv.Client(), srv.URL+"/redirect")
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	if len(sub.Hubs) != 2 || sub.Hubs[1].String() != "https://hub2.example.com/" || sub.Self.String() != srv.URL+"/feed" {
+		t.Fatalf("Got %+v\n", sub)
+	}
+	// No hub in the header, so everything comes from the document
+	sub, err = webLinks.DiscoverWebSub(context.Background(), srv.Client(), srv.URL+"/html")
+	if err != nil || len(sub.Hubs) != 1 || sub.Self.String() != srv.URL+"/topic" {
+		t.Fatalf("Got %+v, %v\n", sub, err)
+	}
+	if _, err := webLinks.DiscoverWebSub(context.Background(), srv.Client(), srv.URL+"/plain"); !errors.Is(err, webLinks.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v\n", err)
+	}
+}
+
+func TestDiscoverWebSubNoSelf(t *testing.T) {
+	t.Parallel()
+	srv := discoveryServer(t, map[string][2]string{"/page": {`<https://hub.example.com/>; rel=hub`, ``}})
+	sub, err := webLinks.DiscoverWebSub(context.Background(), srv.Client(), srv.URL+"/redirect")
+	if err != nil || sub.Self.String() != srv.URL+"/page" {
+		t.Fatalf("Expected the topic to be where it was fetched from, got %+v, %v\n", sub, err)
+	}
+}
+
+func TestDiscoverIndieAuth(t *testing.T) {
+	t.Parallel()
+	srv := discoveryServer(t, map[string][2]string{
+		"/page": {`<https://auth.example.com/auth>; rel=authorization_endpoint`,
+			`<link rel=authorization_endpoint href=https://ignored.example.com/auth>
+<link rel=token_endpoint href=/token>
+<link rel=indieauth-metadata href=/.well-known/oauth-authorization-server>
+<a rel=me href=https://github.com/someone>GitHub</a>
+<a rel="me nofollow" href=https://github.com/someone>again</a>
+<link rel=me href=mailto:someone@example.com>`},
+	})
+	auth, profile, err := webLinks.DiscoverIndieAuth(context.Background(), srv.Client(), srv.URL+"/redirect")
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	if profile.String() != srv.URL+"/page" {
+		t.Fatalf("Got profile %v\n", profile)
+	}
+	if auth.AuthorizationEndpoint.String() != "https://auth.example.com/auth" || auth.TokenEndpoint.String() != srv.URL+"/token" {
+		t.Fatalf("Got %+v\n", auth)
+	}
+	if auth.Metadata.String() != srv.URL+"/.well-known/oauth-authorization-server" {
+		t.Fatalf("Got metadata %v\n", auth.Metadata)
+	}
+	if len(auth.Me) != 2 || auth.Me[0].String() != "https://github.com/someone" || auth.Me[1].String() != "mailto:someone@example.com" {
+		t.Fatalf("Got me %v\n", auth.Me)
+	}
+
+	srv = discoveryServer(t, map[string][2]string{"/page": {``, `<p>nothing`}})
+	auth, _, err = webLinks.DiscoverIndieAuth(context.Background(), srv.Client(), srv.URL+"/page")
+	if err != nil || auth.Metadata != nil || auth.AuthorizationEndpoint != nil || auth.Me != nil {
+		t.Fatalf("Got %+v, %v\n", auth, err)
+	}
+}
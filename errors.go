@@ -0,0 +1,118 @@
+package webLinks
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WarningKind classifies the problems ParseLenient reports.
+type WarningKind string
+
+// The kinds of problem ParseLenient can report.
+const (
+	WarnNoLinks           WarningKind = "no links"
+	WarnMissingBracket    WarningKind = "missing angle bracket"
+	WarnBadTarget         WarningKind = "bad target"
+	WarnUnexpected        WarningKind = "unexpected characters"
+	WarnEmptyParam        WarningKind = "empty parameter"
+	WarnBadParamName      WarningKind = "bad parameter name"
+	WarnMissingValue      WarningKind = "missing value"
+	WarnBadValue          WarningKind = "bad value"
+	WarnUnterminatedQuote WarningKind = "unterminated quote"
+	WarnBadExtValue       WarningKind = "bad extended value"
+	WarnUnknownCharset    WarningKind = "unknown charset"
+	WarnMissingRel        WarningKind = "missing rel"
+	WarnDuplicateParam    WarningKind = "duplicate parameter"
+	WarnNotUTF8           WarningKind = "charset other than UTF-8"
+	WarnBadContinuation   WarningKind = "bad continuation"
+	WarnBadLanguage       WarningKind = "bad language tag"
+	WarnBadMediaType      WarningKind = "bad media type"
+	WarnBadRel            WarningKind = "bad relation type"
+	WarnUnregisteredRel   WarningKind = "unregistered relation type"
+)
+
+// The classes of problem a *SyntaxError can describe, for use with errors.Is.
+var (
+	ErrNoLinks             = errors.New("webLinks: no links")
+	ErrMissingAngleBracket = errors.New("webLinks: missing angle bracket")
+	ErrBadTarget           = errors.New("webLinks: bad link target")
+	ErrUnexpected          = errors.New("webLinks: unexpected characters")
+	ErrEmptyParam          = errors.New("webLinks: empty parameter")
+	ErrBadParamName        = errors.New("webLinks: bad parameter name")
+	ErrMissingValue        = errors.New("webLinks: missing parameter value")
+	ErrBadValue            = errors.New("webLinks: bad parameter value")
+	ErrUnterminatedQuote   = errors.New("webLinks: unterminated quoted string")
+	ErrBadExtValue         = errors.New("webLinks: bad extended value")
+	ErrMissingRel          = errors.New("webLinks: missing rel parameter")
+	ErrDuplicateParam      = errors.New("webLinks: duplicate parameter")
+	ErrNotUTF8             = errors.New("webLinks: extended value not in UTF-8")
+	ErrBadContinuation     = errors.New("webLinks: bad parameter continuation")
+	ErrBadLanguage         = errors.New("webLinks: bad language tag")
+	ErrBadMediaType        = errors.New("webLinks: bad media type")
+	ErrBadRel              = errors.New("webLinks: bad relation type")
+	ErrUnregisteredRel     = errors.New("webLinks: unregistered relation type")
+)
+
+// ErrNotFound is wrapped by the errors returned when discovery finds no link
+// of the relation type it looks for.
+var ErrNotFound = errors.New("webLinks: no link found")
+
+// ErrLimitExceeded is wrapped by the errors returned when a header exceeds
+// one of the limits set on a Parser.
+var ErrLimitExceeded = errors.New("webLinks: limit exceeded")
+
+func limitError(format string, max int) error {
+	return fmt.Errorf("%w: "+format, ErrLimitExceeded, max)
+}
+
+// kindErrors maps each kind of warning to the error reported in its place
+// when parsing strictly.
+var kindErrors = map[WarningKind]error{
+	WarnNoLinks:           ErrNoLinks,
+	WarnMissingBracket:    ErrMissingAngleBracket,
+	WarnBadTarget:         ErrBadTarget,
+	WarnUnexpected:        ErrUnexpected,
+	WarnEmptyParam:        ErrEmptyParam,
+	WarnBadParamName:      ErrBadParamName,
+	WarnMissingValue:      ErrMissingValue,
+	WarnBadValue:          ErrBadValue,
+	WarnUnterminatedQuote: ErrUnterminatedQuote,
+	WarnBadExtValue:       ErrBadExtValue,
+	WarnMissingRel:        ErrMissingRel,
+	WarnDuplicateParam:    ErrDuplicateParam,
+	WarnNotUTF8:           ErrNotUTF8,
+	WarnBadContinuation:   ErrBadContinuation,
+	WarnBadLanguage:       ErrBadLanguage,
+	WarnBadMediaType:      ErrBadMediaType,
+	WarnBadRel:            ErrBadRel,
+	WarnUnregisteredRel:   ErrUnregisteredRel,
+}
+
+// A Warning describes a problem that was found in a header, and worked
+// around, while parsing leniently.
+type Warning struct {
+	Kind   WarningKind
+	Msg    string
+	Offset int    // byte offset of the problem within the header
+	Text   string // the offending part of the header
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s at offset %d: %q", w.Kind, w.Msg, w.Offset, w.Text)
+}
+
+// A SyntaxError describes where, and why, a header failed to parse.
+type SyntaxError struct {
+	Err    error // one of the Err* variables above, classifying the problem
+	Msg    string
+	Offset int    // byte offset of the problem within the header
+	Text   string // the offending part of the header
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("webLinks: %s at offset %d: %q", e.Msg, e.Offset, e.Text)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
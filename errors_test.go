@@ -0,0 +1,124 @@
+package webLinks_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+var warningTests = []struct {
+	input    string
+	links    int
+	warnings []webLinks.WarningKind
+}{
+	{`</a>; rel="next"`, 1, nil},
+	{``, 0, []webLinks.WarningKind{webLinks.WarnNoLinks}},
+	{`foo; rel=next, </a>; rel=next`, 1, []webLinks.WarningKind{webLinks.WarnMissingBracket}},
+	{`</a>; title="a`, 1, []webLinks.WarningKind{webLinks.WarnUnterminatedQuote, webLinks.WarnMissingRel}},
+	{`</a>; rel=next page`, 1, []webLinks.WarningKind{webLinks.WarnBadValue}},
+	{`</a>; rel=next; title*=x-unknown'ru'%D0`, 1, []webLinks.WarningKind{webLinks.WarnUnknownCharset}},
+	{`</a>; rel=next; title*=UTF-8'de'%zz`, 1, []webLinks.WarningKind{webLinks.WarnBadExtValue}},
+	{`</a>; rel=next; title*=UTF-8'de'N%E4chste`, 1, []webLinks.WarningKind{webLinks.WarnBadExtValue}},
+	{`</a>;; rel=next; flag`, 1, []webLinks.WarningKind{webLinks.WarnEmptyParam, webLinks.WarnMissingValue}},
+	{`</a> junk; rel=next`, 1, []webLinks.WarningKind{webLinks.WarnUnexpected}},
+}
+
+func TestParseLenientWarnings(t *testing.T) {
+	t.Parallel()
+	for _, test := range warningTests {
+		links, warnings := webLinks.ParseLenient(test.input)
+		if len(links) != test.links {
+			t.Fatalf("Length mismatch for %q, got %d expected %d\n", test.input, len(links), test.links)
+		}
+		if len(warnings) != len(test.warnings) {
+			t.Fatalf("Warning count mismatch for %q, got %v expected %v\n", test.input, warnings, test.warnings)
+		}
+		for i, w := range warnings {
+			if w.Kind != test.warnings[i] {
+				t.Fatalf("Wrong warning for %q, got %q expected %q\n", test.input, w.Kind, test.warnings[i])
+			}
+		}
+	}
+}
+
+var offsetTests = []struct {
+	input  string
+	offset int
+	text   string
+}{
+	{`</a>; rel=next, </b c>; rel=next`, 17, "/b c"},
+	{`</a>; rel=next, </b>; rel="next`, 26, `"next`},
+	{`</a>; rel=next page`, 10, "next page"},
+	{`</a>; rel=next, foo, </b>; rel=next`, 16, "foo"},
+}
+
+func TestParseStrictErrorOffsets(t *testing.T) {
+	t.Parallel()
+	for _, test := range offsetTests {
+		_, err := webLinks.ParseStrict(test.input)
+		var serr *webLinks.SyntaxError
+		if !errors.As(err, &serr) {
+			t.Fatalf("Expected a *SyntaxError for %q, got %v\n", test.input, err)
+		}
+		if serr.Offset != test.offset || serr.Text != test.text {
+			t.Fatalf("Wrong location for %q, got %d %q expected %d %q\n", test.input, serr.Offset, serr.Text, test.offset, test.text)
+		}
+	}
+}
+
+func TestParseLenientWarningOffsets(t *testing.T) {
+	t.Parallel()
+	for _, test := range offsetTests {
+		_, warnings := webLinks.ParseLenient(test.input)
+		if len(warnings) != 1 {
+			t.Fatalf("Expected one warning for %q, got %v\n", test.input, warnings)
+		}
+		if warnings[0].Offset != test.offset || warnings[0].Text != test.text {
+			t.Fatalf("Wrong location for %q, got %d %q expected %d %q\n", test.input, warnings[0].Offset, warnings[0].Text, test.offset, test.text)
+		}
+	}
+}
+
+func TestParseStrictAggregatesErrors(t *testing.T) {
+	t.Parallel()
+	links, err := webLinks.ParseStrict(`</a>; rel=next page, </b>; rel=next, foo, </c>; rel="next`)
+	if len(links) != 1 || links[0].URI != "/b" {
+		t.Fatalf("Expected only the valid link, got %v\n", links)
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("Expected joined errors, got %v\n", err)
+	}
+	if errs := joined.Unwrap(); len(errs) != 3 {
+		t.Fatalf("Error count mismatch, got %d expected %d: %v\n", len(errs), 3, errs)
+	}
+}
+
+var sentinelTests = []struct {
+	input string
+	err   error
+}{
+	{``, webLinks.ErrNoLinks},
+	{`foo; rel=next`, webLinks.ErrMissingAngleBracket},
+	{`<foo; rel=next`, webLinks.ErrMissingAngleBracket},
+	{`</a b>; rel=next`, webLinks.ErrBadTarget},
+	{`</a> junk; rel=next`, webLinks.ErrUnexpected},
+	{`</a>;; rel=next`, webLinks.ErrEmptyParam},
+	{`</a>; r@l=next`, webLinks.ErrBadParamName},
+	{`</a>; rel`, webLinks.ErrMissingValue},
+	{`</a>; rel=next page`, webLinks.ErrBadValue},
+	{`</a>; rel="next`, webLinks.ErrUnterminatedQuote},
+	{`</a>; rel=next; title*=UTF-8'de'%zz`, webLinks.ErrBadExtValue},
+	{`</a>; title=next`, webLinks.ErrMissingRel},
+}
+
+func TestParseStrictSentinels(t *testing.T) {
+	t.Parallel()
+	for _, test := range sentinelTests {
+		_, err := webLinks.ParseStrict(test.input)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("Expected %v for %q, got %v\n", test.err, test.input, err)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package webLinks
+
+import (
+	"sort"
+	"strings"
+)
+
+// Quote returns s as a quoted-string, per RFC 7230, section 3.2.6: wrapped in
+// double quotes, with any double quotes or backslashes in s escaped by a
+// backslash. It is the inverse of how quoted parameter values are parsed.
+func Quote(s string) string {
+	if strings.IndexAny(s, `"\`) == -1 {
+		return `"` + s + `"`
+	}
+	return string(appendQuote(make([]byte, 0, len(s)+4), s))
+}
+
+// appendQuote appends s to dst as a quoted-string, as Quote returns it.
+func appendQuote(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			dst = append(dst, '\\')
+		}
+		dst = append(dst, s[i])
+	}
+	return append(dst, '"')
+}
+
+// String formats the link as a Link header field value, as described in
+// RFC 8288, section 3: its target in angle brackets, followed by each of its
+// params, rel first and the others by name. Characters that may never appear
+// unencoded in a URI, which would otherwise make for a malformed header, are
+// percent encoded in the target. Params that appeared more than once, as held
+// in Duplicates, are written out each time, and those whose names aren't
+// tokens are left out.
+//
+// Values are written as tokens where they can be, and as quoted-strings
+// otherwise. Values in a charset other than US-ASCII, or that need one, and
+// those holding control characters, are written as UTF-8 ext-values, with a
+// "*" appended to their name, as described in RFC 8187. Ext-values that
+// couldn't be decoded when parsed, being in an unknown charset or badly
+// percent encoded, are written as they appeared.
+//
+// Links parsed losslessly, see Parser.Lossless, are instead written exactly
+// as they appeared, unless they have been modified since.
+func (l Link) String() string {
+	if v := l.intact(); v != nil {
+		return v.text
+	}
+	return l.formatted()
+}
+
+// formatted returns the link formatted afresh, whatever it looked like.
+func (l Link) formatted() string {
+	return string(l.format(nil))
+}
+
+// AppendFormat is like String, but appends the formatted link to dst and
+// returns the extended buffer, so that a buffer can be reused to avoid
+// allocating, as time.Time.AppendFormat does.
+func (l Link) AppendFormat(dst []byte) []byte {
+	if v := l.intact(); v != nil {
+		return append(dst, v.text...)
+	}
+	return l.format(dst)
+}
+
+// verbatim is a link as it appeared in the header it was parsed from, see
+// Parser.Lossless.
+type verbatim struct {
+	src       *string // the header, shared by the links parsed from it
+	sep       string  // what came between the previous link and this one
+	text      string
+	tail      string // what came after the link, if it was the last one
+	formatted string // the link formatted afresh, as it was parsed
+}
+
+// intact returns the link as it appeared in the header, or nil if it wasn't
+// parsed losslessly or has been modified since.
+func (l Link) intact() *verbatim {
+	if l.verbatim == nil || l.formatted() != l.verbatim.formatted {
+		return nil
+	}
+	return l.verbatim
+}
+
+func (l Link) format(dst []byte) []byte {
+	dst = append(dst, '<')
+	dst = appendEscapedURI(dst, l.URI)
+	dst = append(dst, '>')
+	var buf [16]string
+	for _, name := range l.paramNames(buf[:0]) {
+		params, ok := l.Duplicates[name]
+		if !ok {
+			params = []Param{l.Params[name]}
+		}
+		for _, param := range params {
+			dst = append(dst, "; "...)
+			dst = param.format(dst, name)
+		}
+	}
+	return dst
+}
+
+// paramNames appends the names of the link's params to names, in the order
+// they are formatted in: rel first, and the others sorted. Names that aren't
+// tokens, like the empty one a lenient parse may leave, can't be written and
+// are left out. Links seldom have more than a few params, so they are sorted
+// by insertion, which needn't allocate.
+func (l Link) paramNames(names []string) []string {
+	for name := range l.Params {
+		if !isToken(name) {
+			continue
+		}
+		names = append(names, name)
+		for i := len(names) - 1; i > 0 && lessParamName(names[i], names[i-1]); i-- {
+			names[i], names[i-1] = names[i-1], names[i]
+		}
+	}
+	return names
+}
+
+// lessParamName orders param names as they are formatted in.
+func lessParamName(a, b string) bool {
+	if rel := strings.EqualFold(a, "rel"); rel != strings.EqualFold(b, "rel") {
+		return rel
+	}
+	return a < b
+}
+
+// format appends the param called name to dst, along with its value.
+func (p Param) format(dst []byte, name string) []byte {
+	dst = append(dst, name...)
+	if p.Flag {
+		return dst
+	}
+	if p.undecoded && p.Raw != "" {
+		// There's no telling what Value holds, so it is left as it was
+		dst = append(dst, "*="...)
+		return append(dst, p.Raw...)
+	}
+	if p.extended() {
+		dst = append(dst, "*=UTF-8'"...)
+		if p.Enc != "" && !strings.EqualFold(p.Enc, "us-ascii") {
+			// Otherwise the language is only the default
+			dst = append(dst, p.Lang...)
+		}
+		dst = append(dst, '\'')
+		return appendPctEncode(dst, p.Value)
+	}
+	dst = append(dst, '=')
+	if isToken(p.Value) {
+		return append(dst, p.Value...)
+	}
+	return appendQuote(dst, p.Value)
+}
+
+// extended reports whether the param must be written as an ext-value, being
+// in a charset of its own or holding anything but printable US-ASCII, which
+// a quoted-string can't hold.
+func (p Param) extended() bool {
+	return p.Enc != "" && !strings.EqualFold(p.Enc, "us-ascii") || !isPrintable(p.Value)
+}
+
+// appendPctEncode appends s to dst with every byte that isn't an attr-char
+// percent encoded, for use as the value of an ext-value. It is the inverse of
+// pctDecode.
+func appendPctEncode(dst []byte, s string) []byte {
+	const hex = "0123456789ABCDEF"
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; strings.IndexByte(attrChars, c) != -1 {
+			dst = append(dst, c)
+		} else {
+			dst = append(dst, '%', hex[c>>4], hex[c&0xf])
+		}
+	}
+	return dst
+}
+
+// String formats the links as a Link header field value, each as described
+// by Link.String and separated by commas. Links parsed losslessly that still
+// follow each other as they did in the header keep whatever separated them,
+// so that a header whose links are all left alone is reproduced exactly.
+func (l Links) String() string {
+	return string(l.AppendFormat(nil))
+}
+
+// AppendFormat is like String, but appends the formatted links to dst and
+// returns the extended buffer, so that servers formatting Link headers for
+// every response can reuse a buffer rather than allocate one each time.
+// Formatting links that were parsed losslessly may allocate still.
+func (l Links) AppendFormat(dst []byte) []byte {
+	var prev *verbatim
+	for i, link := range l {
+		v := link.intact()
+		switch {
+		case v != nil && (i == 0 && link.Index == 0 || prev != nil && prev.src == v.src && l[i-1].Index == link.Index-1):
+			// As it was in the header, following the same link
+			dst = append(dst, v.sep...)
+		case i > 0:
+			dst = append(dst, ", "...)
+		}
+		if v != nil {
+			dst = append(dst, v.text...)
+		} else {
+			dst = link.format(dst)
+		}
+		prev = v
+	}
+	if prev != nil {
+		dst = append(dst, prev.tail...)
+	}
+	return dst
+}
+
+// Canonical formats the link in a normalized form, so that links that mean
+// the same thing are formatted the same, however they were written: param
+// names lowercased and sorted, values written as tokens where they can be
+// and quoted-strings otherwise, and as UTF-8 ext-values only if they must
+// be, holding anything but printable US-ASCII or having a language of their
+// own. Relation types are lowercased if registered, and sorted. The target
+// is written as it is, but for characters that may never appear unencoded in
+// a URI, which are percent encoded as String does.
+func (l Link) Canonical() string {
+	return string(l.canonical(nil))
+}
+
+func (l Link) canonical(dst []byte) []byte {
+	dst = append(dst, '<')
+	dst = appendEscapedURI(dst, l.URI)
+	dst = append(dst, '>')
+	names := l.paramNames(nil)
+	sort.SliceStable(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	for _, name := range names {
+		params, ok := l.Duplicates[name]
+		if !ok {
+			params = []Param{l.Params[name]}
+		}
+		name = strings.ToLower(name)
+		for _, param := range params {
+			dst = append(dst, "; "...)
+			switch {
+			case name == "rel" && !param.Flag:
+				param = Param{Value: l.relSet()}
+			case !isPrintable(param.Value):
+				if param.Enc == "" || strings.EqualFold(param.Enc, "us-ascii") {
+					param.Lang = ""
+				}
+				param.Enc = "UTF-8"
+			case param.Lang == "" || param.Enc == "" || strings.EqualFold(param.Enc, "us-ascii"):
+				// Nothing that needs an ext-value
+				param.Enc = ""
+			}
+			dst = param.format(dst, name)
+		}
+	}
+	return dst
+}
+
+// isPrintable reports whether s holds nothing but printable US-ASCII, which
+// a quoted-string can hold as it is.
+func isPrintable(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < ' ' && c != '\t') || c >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// Canonical formats the links as a Link header field value, each as
+// Link.Canonical does, in the same order, separated by commas.
+func (l Links) Canonical() string {
+	var dst []byte
+	for i, link := range l {
+		if i > 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = link.canonical(dst)
+	}
+	return string(dst)
+}
+
+// Fields formats the links as Link header field values, as String does, but
+// spread over as many fields as it takes to keep each no longer than max
+// bytes, for proxies that reject long header fields. Links are never split
+// across fields, and stay in order: a link that is longer than max on its own
+// gets a field of its own. Every link is put in one field if max isn't
+// positive. Each of the values returned is for a "Link" header field of its
+// own, as with http.Header.Add.
+func (l Links) Fields(max int) []string {
+	if len(l) == 0 {
+		return nil
+	}
+	var fields []string
+	var field, buf []byte
+	for _, link := range l {
+		buf = link.AppendFormat(buf[:0])
+		if len(field) > 0 && max > 0 && len(field)+len(", ")+len(buf) > max {
+			fields = append(fields, string(field))
+			field = field[:0]
+		}
+		if len(field) > 0 {
+			field = append(field, ", "...)
+		}
+		field = append(field, buf...)
+	}
+	return append(fields, string(field))
+}
@@ -0,0 +1,308 @@
+package webLinks_test
+
+import (
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+var quoteTests = []struct {
+	value  string
+	quoted string
+}{
+	{``, `""`},
+	{`previous chapter`, `"previous chapter"`},
+	{`say "hi"`, `"say \"hi\""`},
+	{`C:\path\`, `"C:\\path\\"`},
+	{`a, b; c`, `"a, b; c"`},
+}
+
+func TestQuote(t *testing.T) {
+	t.Parallel()
+	for _, test := range quoteTests {
+		if quoted := webLinks.Quote(test.value); quoted != test.quoted {
+			t.Fatalf("Got %s expected %s\n", quoted, test.quoted)
+		}
+	}
+}
+
+func TestQuoteRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, test := range quoteTests {
+		links := webLinks.Parse(`</a>; rel=next; title=` + webLinks.Quote(test.value))
+		if title := links[0].Params["title"].Value; title != test.value {
+			t.Fatalf("Got %q expected %q\n", title, test.value)
+		}
+	}
+}
+
+var unquoteTests = []struct {
+	input string
+	value string
+}{
+	{`</a>; rel=next; title="say \"hi\""`, `say "hi"`},
+	{`</a>; rel=next; title="back\\slash"`, `back\slash`},
+	// Anything may be escaped, it stands for itself
+	{`</a>; rel=next; title="\a\b\c"`, `abc`},
+	{`</a>; rel=next; title="\\\""`, `\"`},
+	{`</a>; rel=next; title="a\, b"`, `a, b`},
+}
+
+func TestParseQuotedPairs(t *testing.T) {
+	t.Parallel()
+	for _, test := range unquoteTests {
+		links, err := webLinks.ParseStrict(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s\n", test.input, err)
+		}
+		if title := links[0].Params["title"].Value; title != test.value {
+			t.Fatalf("Got %q expected %q\n", title, test.value)
+		}
+	}
+}
+
+var formatTests = []struct {
+	link   webLinks.Link
+	output string
+}{
+	{webLinks.Link{URI: "/a"}, `</a>`},
+	{
+		webLinks.Link{URI: "/a", Params: map[string]webLinks.Param{"rel": {Value: "next"}}},
+		`</a>; rel=next`,
+	},
+	{
+		webLinks.Link{URI: "http://example.com/?page=2", Params: map[string]webLinks.Param{
+			"title": {Value: `Page "2"`},
+			"rel":   {Value: "next http://example.com/rel/page"},
+			"act":   {Flag: true},
+			"empty": {Value: ""},
+		}},
+		`<http://example.com/?page=2>; rel="next http://example.com/rel/page"; act; empty=""; title="Page \"2\""`,
+	},
+	{
+		webLinks.Link{URI: "/a", Params: map[string]webLinks.Param{
+			"rel":   {Value: "next"},
+			"title": {Value: "Nächste Seite", Enc: "UTF-8", Lang: "de"},
+		}},
+		`</a>; rel=next; title*=UTF-8'de'N%C3%A4chste%20Seite`,
+	},
+	{
+		webLinks.Link{URI: "/a", Params: map[string]webLinks.Param{
+			"rel":   {Value: "next"},
+			"title": {Value: "€"},
+		}},
+		`</a>; rel=next; title*=UTF-8''%E2%82%AC`,
+	},
+	{
+		webLinks.Link{URI: "/a", Params: map[string]webLinks.Param{
+			"rel": {Value: "next"},
+			"":    {Value: "x"},
+			"a b": {Value: "y"},
+		}},
+		`</a>; rel=next`,
+	},
+}
+
+func TestLinkString(t *testing.T) {
+	t.Parallel()
+	for _, test := range formatTests {
+		if output := test.link.String(); output != test.output {
+			t.Fatalf("Got %s expected %s\n", output, test.output)
+		}
+	}
+}
+
+var roundTripTests = []string{
+	`</a>; rel=next`,
+	`</a>; rel=next; title="Page 2", </b>; rel=prev; anchor="#x"`,
+	`</a>; rel=alternate; hreflang=de; hreflang=en; title="Next"; title*=UTF-8'de'N%C3%A4chste`,
+	`</a>; rel=next; title*=iso-8859-1'de'n%E4chstes`,
+	`</a>; rel="next prev"; foo; bar=""; title="a \"b\" c\\d"`,
+}
+
+func TestLinksStringRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, input := range roundTripTests {
+		links := webLinks.Parse(input)
+		output := links.String()
+		again, warnings := webLinks.ParseLenient(output)
+		for _, w := range warnings {
+			if w.Kind != webLinks.WarnMissingValue {
+				t.Fatalf("Unexpected warning for %s: %v\n", output, w)
+			}
+		}
+		if again.String() != output {
+			t.Fatalf("Got %s then %s for %s\n", output, again.String(), input)
+		}
+		for i, link := range again {
+			for name, param := range links[i].Params {
+				if link.Params[name].Value != param.Value {
+					t.Fatalf("Got %q expected %q for %q in %s\n", link.Params[name].Value, param.Value, name, output)
+				}
+			}
+		}
+	}
+}
+
+var losslessTests = []string{
+	`</a>;rel=next`,
+	`  <http://example.com/a> ; REL="next" ;title=Page, </b>;  rel="prev";hreflang=en;hreflang=de  `,
+	`</a>; rel=next,, ,</b>; rel=prev,`,
+	"</a>; rel=next;\ttitle*=UTF-8''%C3%A9t%C3%A9; title*0=x; title*1=y",
+	`</a>; rel=next, junk, </b>; rel="prev", <c; rel=up`,
+}
+
+var undecodedTests = []struct {
+	input  string
+	output string
+}{
+	// An unknown charset
+	{`</a>; rel=next; title*=x'y'z`, `</a>; rel=next; title*=x'y'z`},
+	// A bad escape
+	{`</a>; rel=next; title*=UTF-8''%`, `</a>; rel=next; title*=UTF-8''%`},
+	{`</a>; rel=next; title*0*=x'y'a%20; title*1*=b`, `</a>; rel=next; title*=x'y'a%20b`},
+}
+
+func TestUndecodedRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, test := range undecodedTests {
+		links, _ := webLinks.ParseLenient(test.input)
+		if output := links.String(); output != test.output {
+			t.Fatalf("Got %s expected %s\n", output, test.output)
+		}
+		if again, _ := webLinks.ParseLenient(test.output); again.String() != test.output {
+			t.Fatalf("Got %s then %s\n", test.output, again.String())
+		}
+	}
+}
+
+func TestLossless(t *testing.T) {
+	t.Parallel()
+	p := webLinks.NewParser(webLinks.WithLossless())
+	for _, input := range losslessTests {
+		links, _ := p.ParseLenient(input)
+		if output := links.String(); output != input {
+			t.Fatalf("Mismatch, got %q expected %q\n", output, input)
+		}
+		bytes, _ := p.ParseBytes([]byte(input))
+		if output := bytes.String(); output != input {
+			t.Fatalf("Mismatch for bytes, got %q expected %q\n", output, input)
+		}
+	}
+}
+
+func TestLosslessModified(t *testing.T) {
+	t.Parallel()
+	const input = ` </a> ;rel=next,</b> ; rel=prev , </c>;rel=up `
+	links := webLinks.Parse(input, webLinks.WithLossless())
+	if links[1].String() != `</b> ; rel=prev` {
+		t.Fatalf("Got %q\n", links[1].String())
+	}
+	links[1].Params["title"] = webLinks.Param{Value: "B"}
+	if output := links.String(); output != ` </a> ;rel=next, </b>; rel=prev; title=B, </c>;rel=up ` {
+		t.Fatalf("Expected the modified link to be formatted afresh, got %q\n", output)
+	}
+	links.Remove("next")
+	if output := links.String(); output != `</b>; rel=prev; title=B, </c>;rel=up ` {
+		t.Fatalf("Got %q\n", output)
+	}
+	links = webLinks.Parse(input, webLinks.WithLossless())
+	links.Remove("prev")
+	if output := links.String(); output != ` </a> ;rel=next, </c>;rel=up ` {
+		t.Fatalf("Expected unrelated separators not to be kept, got %q\n", output)
+	}
+	if output := webLinks.Parse(input).String(); output != `</a>; rel=next, </b>; rel=prev, </c>; rel=up` {
+		t.Fatalf("Expected links to be formatted afresh by default, got %q\n", output)
+	}
+}
+
+var canonicalTests = []struct {
+	input    string
+	expected string
+}{
+	{`</a>; Title="Page"; REL="next"; anchor="#x"`, `</a>; anchor=#x; rel=next; title=Page`},
+	{`</a>; rel="Next  last last"`, `</a>; rel="last next"`},
+	{`</a>; rel="http://example.com/Rel next"`, `</a>; rel="http://example.com/Rel next"`},
+	{`</a>; rel=next; title*=UTF-8''plain`, `</a>; rel=next; title=plain`},
+	{`</a>; rel=next; title*=UTF-8'de'plain`, `</a>; rel=next; title*=UTF-8'de'plain`},
+	{`</a>; rel=next; title*=iso-8859-1''n%E4chstes`, `</a>; rel=next; title*=UTF-8''n%C3%A4chstes`},
+	{`</a>; rel=next; title="two words"`, `</a>; rel=next; title="two words"`},
+	{`</a>; rel=next; hreflang=en; Hreflang=de; b; a=1`, `</a>; a=1; b; hreflang=en; hreflang=de; rel=next`},
+}
+
+func TestCanonical(t *testing.T) {
+	t.Parallel()
+	for _, test := range canonicalTests {
+		if output := webLinks.Parse(test.input)[0].Canonical(); output != test.expected {
+			t.Fatalf("Mismatch for %q, got %q expected %q\n", test.input, output, test.expected)
+		}
+	}
+	links := webLinks.Links{
+		{URI: "/a", Params: map[string]webLinks.Param{"rel": {Value: "next"}, "title": {Value: "tab\x01"}}},
+		webLinks.Parse(`</b>; REL=prev`)[0],
+	}
+	if output := links.Canonical(); output != `</a>; rel=next; title*=UTF-8''tab%01, </b>; rel=prev` {
+		t.Fatalf("Got %q\n", output)
+	}
+}
+
+// Not parallel, as other tests' allocations would be counted
+func TestAppendFormat(t *testing.T) {
+	links := webLinks.Parse(`</a>; rel=next; title="Page 2"; hreflang=en; hreflang=de, </b>; rel=prev; title*=UTF-8'de'n%C3%A4chste`)
+	buf := []byte("Link: ")
+	if output := string(links.AppendFormat(buf)); output != "Link: "+links.String() {
+		t.Fatalf("Got %q\n", output)
+	}
+	if output := string(links[1].AppendFormat(nil)); output != links[1].String() {
+		t.Fatalf("Got %q\n", output)
+	}
+	buf = make([]byte, 0, 256)
+	if allocs := testing.AllocsPerRun(100, func() {
+		buf = links.AppendFormat(buf[:0])
+	}); allocs != 0 {
+		t.Fatalf("Expected no allocations, got %v\n", allocs)
+	}
+}
+
+func BenchmarkLinksAppendFormat(b *testing.B) {
+	links := webLinks.Parse(`</TheBook/chapter2>; rel="previous"; title*=UTF-8'de'letztes%20Kapitel, </TheBook/chapter4>; rel="next"; title*=UTF-8'de'n%c3%a4chstes%20Kapitel`)
+	buf := make([]byte, 0, 256)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = links.AppendFormat(buf[:0])
+	}
+}
+
+func TestLinksFields(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</1>; rel=next, </22>; rel=prev, </333>; rel=first; title="a long title", </4>; rel=last`)
+	fields := links.Fields(31)
+	expected := []string{
+		`</1>; rel=next, </22>; rel=prev`,
+		`</333>; rel=first; title="a long title"`,
+		`</4>; rel=last`,
+	}
+	if len(fields) != len(expected) {
+		t.Fatalf("Length mismatch, got %q\n", fields)
+	}
+	for i, field := range fields {
+		if field != expected[i] {
+			t.Fatalf("Mismatch at %d, got %q expected %q\n", i, field, expected[i])
+		}
+	}
+	if reparsed := webLinks.ParseMultiple(fields); !reparsed.Equal(links) {
+		t.Fatalf("Expected the fields to hold the same links, got %v\n", reparsed)
+	}
+
+	if fields := links.Fields(0); len(fields) != 1 || fields[0] != links.String() {
+		t.Fatalf("Expected a single field, got %q\n", fields)
+	}
+	if fields := links.Fields(30); len(fields) != 4 || fields[0] != `</1>; rel=next` {
+		t.Fatalf("Expected the limit to be exceeded by a byte, got %q\n", fields)
+	}
+	if fields := (webLinks.Links{}).Fields(10); fields != nil {
+		t.Fatalf("Expected no fields, got %q\n", fields)
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/conslo/webLinks
+
+go 1.20
+
+require (
+	golang.org/x/net v0.35.0
+	golang.org/x/text v0.22.0
+)
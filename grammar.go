@@ -0,0 +1,158 @@
+package webLinks
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Character classes from RFC 7230, section 3.2.6, RFC 8187, section 3.2.1
+// and RFC 3986, used when validating headers strictly.
+const (
+	alphaDigit  = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	tchars      = alphaDigit + "!#$%&'*+-.^_`|~"
+	attrChars   = alphaDigit + "!#$&+-.^_`|~"
+	charsetChar = alphaDigit + "!#$%&+-^_`{}~"
+	langChars   = alphaDigit + "-"
+)
+
+// isToken reports whether s is a token, per RFC 7230.
+func isToken(s string) bool {
+	return s != "" && onlyChars(s, tchars)
+}
+
+// onlyChars reports whether every byte of s is one of chars.
+func onlyChars(s, chars string) bool {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(chars, s[i]) == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// isASCII reports whether s holds nothing but US-ASCII.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// isExtValue reports whether s is an ext-value, per RFC 8187.
+func isExtValue(s string) bool {
+	parts := strings.SplitN(s, "'", 3)
+	if len(parts) != 3 {
+		return false
+	}
+	charset, lang, value := parts[0], parts[1], parts[2]
+	if charset == "" || !onlyChars(charset, charsetChar) || !onlyChars(lang, langChars) {
+		return false
+	}
+	return isPctEncoded(value)
+}
+
+// isPctEncoded reports whether s is made of attr-chars and pct-encoded
+// octets only, as the value of an ext-value or of any but the first encoded
+// section of an RFC 2231 continuation is.
+func isPctEncoded(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' {
+			if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+				return false
+			}
+			i += 2
+		} else if strings.IndexByte(attrChars, s[i]) == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// isURIReference does a cheap sanity check that s could be a URI-reference:
+// it must not contain whitespace, controls or characters that RFC 3986 never
+// allows unencoded.
+func isURIReference(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if needsEscape(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// uriUnsafe holds the characters isURIReference rejects that are printable
+// US-ASCII.
+const uriUnsafe = ` "<>\^` + "`{|}"
+
+// needsEscape reports whether c may never appear unencoded in a
+// URI-reference.
+func needsEscape(c byte) bool {
+	return c < ' ' || c >= 0x7f || strings.IndexByte(uriUnsafe, c) != -1
+}
+
+// appendEscapedURI appends s to dst with every byte that may never appear
+// unencoded in a URI-reference percent encoded, like spaces and the UTF-8
+// encoding of non-ASCII characters, so that it can be written between angle
+// brackets. Anything that is already percent encoded is left alone.
+func appendEscapedURI(dst []byte, s string) []byte {
+	const hex = "0123456789ABCDEF"
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; needsEscape(c) {
+			dst = append(dst, '%', hex[c>>4], hex[c&0xf])
+		} else {
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// escapeURI returns s escaped as appendEscapedURI does.
+func escapeURI(s string) string {
+	if isURIReference(s) {
+		return s
+	}
+	return string(appendEscapedURI(make([]byte, 0, len(s)+8), s))
+}
+
+// hasBadEscape reports whether s has a '%' that isn't followed by two hex
+// digits, which no URI-reference may have.
+func hasBadEscape(s string) bool {
+	for i := strings.IndexByte(s, '%'); i != -1; i = strings.IndexByte(s, '%') {
+		if i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			return true
+		}
+		s = s[i+3:]
+	}
+	return false
+}
+
+// isRelType reports whether s is a relation type, per RFC 8288, section 3.3:
+// either a registered type or an absolute URI. Registered types are matched
+// case-insensitively, as they are compared.
+func isRelType(s string) bool {
+	if isRegisteredRel(s) {
+		return true
+	}
+	if s == "" || !isURIReference(s) {
+		return false
+	}
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs()
+}
+
+// isRegisteredRel reports whether s looks like a registered relation type,
+// rather than an extension one, ignoring case.
+func isRegisteredRel(s string) bool {
+	if s == "" {
+		return false
+	}
+	c := s[0] | 0x20
+	return 'a' <= c && c <= 'z' && onlyChars(s, alphaDigit+".-")
+}
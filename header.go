@@ -0,0 +1,129 @@
+package webLinks
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ParseMultiple parses several "Link" header field values, as found when the
+// header appears more than once in a message, and returns all their links in
+// order. Each value is parsed just like Parse would, though links are
+// indexed across all of them.
+func ParseMultiple(values []string) Links {
+	var links Links
+	for _, value := range values {
+		for _, link := range Parse(value) {
+			link.Index = len(links)
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// ParseHeader parses every "Link" field in h. Besides the canonical "Link"
+// key, which is parsed first, keys spelt with any other case are also
+// honored, in sorted order, for headers that weren't built through
+// http.Header's methods.
+func ParseHeader(h http.Header) Links {
+	return ParseMultiple(linkValues(h))
+}
+
+// linkValues returns every "Link" field value in h.
+func linkValues(h http.Header) []string {
+	values := h["Link"]
+	var others []string
+	for key := range h {
+		if key != "Link" && strings.EqualFold(key, "Link") {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	for _, key := range others {
+		values = append(values[:len(values):len(values)], h[key]...)
+	}
+	return values
+}
+
+// FromResponse parses the "Link" headers of resp, resolving relative targets
+// and anchors against the URL that resp was retrieved from, or against its
+// Content-Location when it has one. Targets and anchors that aren't valid
+// URI-references are left as they are, and reported by the returned error.
+func FromResponse(resp *http.Response) (Links, error) {
+	links := ParseHeader(resp.Header)
+	base := ResponseBase(resp)
+	if base == nil {
+		return links, nil
+	}
+	return links.resolve(base)
+}
+
+// ResponseBase returns the base URI relative references in resp's headers
+// are resolved against, per RFC 3986, section 5.1, or nil if there isn't
+// one. That is the URI resp was retrieved from, which for a client's
+// response is that of the request after any redirects were followed, as
+// resp.Request holds, unless resp has a Content-Location, which is then
+// resolved against it, as RFC 8288, section 3.2 and RFC 7231, section
+// 3.1.4.2 describe.
+//
+// For responses to requests whose URL isn't absolute, as servers receive
+// them, the request's Host is used, along with https if resp went over TLS.
+func ResponseBase(resp *http.Response) *url.URL {
+	var base *url.URL
+	if resp.Request != nil && resp.Request.URL != nil {
+		u := *resp.Request.URL
+		if !u.IsAbs() && resp.Request.Host != "" {
+			u.Scheme, u.Host = "http", resp.Request.Host
+			if resp.TLS != nil {
+				u.Scheme = "https"
+			}
+		}
+		u.Fragment, u.RawFragment = "", ""
+		base = &u
+	}
+	if loc := resp.Header.Get("Content-Location"); loc != "" {
+		if ref, err := url.Parse(loc); err == nil {
+			if base != nil {
+				return base.ResolveReference(ref)
+			}
+			if ref.IsAbs() {
+				return ref
+			}
+		}
+	}
+	return base
+}
+
+// SetLinks sets the "Link" header of the response w writes to links,
+// formatted as Links.Build does into a single field, replacing any links
+// already set. No links at all remove the header. Links that Build finds
+// invalid, which might otherwise inject links of their own through their
+// params, leave the header alone, and the error is returned. As with any
+// header, it must be set before w.WriteHeader or w.Write is called. See
+// Links.Fields to spread many links over several fields.
+func SetLinks(w http.ResponseWriter, links Links) error {
+	if len(links) == 0 {
+		w.Header().Del("Link")
+		return nil
+	}
+	value, err := links.Build()
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Link", value)
+	return nil
+}
+
+// AddLink adds a link to the "Link" header of the response w writes to,
+// keeping any links already set, in a field of its own. It returns the error
+// Links.Build does for the link, without adding it, if it isn't valid. It
+// must be called before w.WriteHeader or w.Write is.
+func AddLink(w http.ResponseWriter, link Link) error {
+	value, err := Links{link}.Build()
+	if err != nil {
+		return err
+	}
+	w.Header().Add("Link", value)
+	return nil
+}
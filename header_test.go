@@ -0,0 +1,214 @@
+package webLinks_test
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestParseMultiple(t *testing.T) {
+	t.Parallel()
+	links := webLinks.ParseMultiple([]string{
+		`</1>; rel="first", </2>; rel="prev"`,
+		``,
+		`</4>; rel="next"; title="a, b"`,
+		`</9>; rel="last"`,
+	})
+	expected := []string{"/1", "/2", "/4", "/9"}
+	if len(links) != len(expected) {
+		t.Fatalf("Length mismatch, got %d expected %d\n", len(links), len(expected))
+	}
+	for i, link := range links {
+		if link.URI != expected[i] {
+			t.Fatalf("Got the wrong URI, got %q expected %q\n", link.URI, expected[i])
+		}
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	t.Parallel()
+	h := http.Header{}
+	h.Add("Link", `</1>; rel="first"`)
+	h.Add("Link", `</2>; rel="prev", </4>; rel="next"`)
+	h["link"] = []string{`</9>; rel="last"`}
+	h.Set("Content-Type", "text/plain")
+
+	links := webLinks.ParseHeader(h)
+	expected := []string{"/1", "/2", "/4", "/9"}
+	if len(links) != len(expected) {
+		t.Fatalf("Length mismatch, got %d expected %d\n", len(links), len(expected))
+	}
+	for i, link := range links {
+		if link.URI != expected[i] {
+			t.Fatalf("Got the wrong URI, got %q expected %q\n", link.URI, expected[i])
+		}
+	}
+	if len(h["Link"]) != 2 {
+		t.Fatalf("ParseHeader modified the header\n")
+	}
+}
+
+var responseTests = []struct {
+	request  string
+	location string
+	links    string
+	expected []string
+}{
+	{
+		"https://api.example.com/items?page=2",
+		"",
+		`<https://api.example.com/items?page=3>; rel="next", </items?page=1>; rel="prev", <?page=9>; rel="last"`,
+		[]string{"https://api.example.com/items?page=3", "https://api.example.com/items?page=1", "https://api.example.com/items?page=9"},
+	},
+	{
+		"https://example.com/a/b",
+		"/docs/current/",
+		`<chapter2>; rel="next", <../>; rel="up"`,
+		[]string{"https://example.com/docs/current/chapter2", "https://example.com/docs/"},
+	},
+	{
+		"",
+		"https://example.org/base/",
+		`<x>; rel="next"`,
+		[]string{"https://example.org/base/x"},
+	},
+	{
+		"",
+		"",
+		`<x>; rel="next"`,
+		[]string{"x"},
+	},
+}
+
+func TestFromResponse(t *testing.T) {
+	t.Parallel()
+	for _, test := range responseTests {
+		resp := &http.Response{Header: http.Header{}}
+		if test.request != "" {
+			req, err := http.NewRequest("GET", test.request, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Request = req
+		}
+		if test.location != "" {
+			resp.Header.Set("Content-Location", test.location)
+		}
+		resp.Header.Set("Link", test.links)
+
+		links, err := webLinks.FromResponse(resp)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s\n", test.links, err)
+		}
+		if len(links) != len(test.expected) {
+			t.Fatalf("Length mismatch, got %d expected %d\n", len(links), len(test.expected))
+		}
+		for i, link := range links {
+			if link.URI != test.expected[i] {
+				t.Fatalf("Got the wrong URI, got %q expected %q\n", link.URI, test.expected[i])
+			}
+		}
+	}
+}
+
+func TestFromResponseBadTarget(t *testing.T) {
+	t.Parallel()
+	req, _ := http.NewRequest("GET", "https://example.com/", nil)
+	resp := &http.Response{Header: http.Header{}, Request: req}
+	resp.Header.Set("Link", `<http://[::1>; rel="next", </ok>; rel="prev"`)
+	links, err := webLinks.FromResponse(resp)
+	if err == nil {
+		t.Fatalf("Expected an error for the bad target\n")
+	}
+	if len(links) != 2 || links[0].URI != "http://[::1" || links[1].URI != "https://example.com/ok" {
+		t.Fatalf("Got unexpected links %v\n", links)
+	}
+}
+
+func TestResponseBaseRedirect(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old/list", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new/dir/list", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new/dir/list", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<page2>; rel=next`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/old/list#top")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if base := webLinks.ResponseBase(resp); base.String() != server.URL+"/new/dir/list" {
+		t.Fatalf("Got %v expected %s\n", base, server.URL+"/new/dir/list")
+	}
+	links, err := webLinks.FromResponse(resp)
+	if err != nil || links[0].URI != server.URL+"/new/dir/page2" {
+		t.Fatalf("Got %v %v\n", links, err)
+	}
+}
+
+func TestResponseBaseServer(t *testing.T) {
+	t.Parallel()
+	req := httptest.NewRequest("GET", "/items?page=2", nil)
+	req.Host = "api.example.com"
+	resp := &http.Response{Header: http.Header{}, Request: req, TLS: req.TLS}
+	if base := webLinks.ResponseBase(resp); base.String() != "http://api.example.com/items?page=2" {
+		t.Fatalf("Got %v\n", base)
+	}
+	resp.TLS = &tls.ConnectionState{}
+	resp.Header.Set("Content-Location", "/items/2")
+	if base := webLinks.ResponseBase(resp); base.String() != "https://api.example.com/items/2" {
+		t.Fatalf("Got %v\n", base)
+	}
+	if base := webLinks.ResponseBase(&http.Response{Header: http.Header{}}); base != nil {
+		t.Fatalf("Got %v expected nil\n", base)
+	}
+}
+
+func TestSetLinks(t *testing.T) {
+	t.Parallel()
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Link", `</old>; rel=prev`)
+	if err := webLinks.SetLinks(rec, webLinks.Parse(`</2>; rel=next, </9>; rel=last`)); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	if err := webLinks.AddLink(rec, webLinks.BuildLink("/1").Rel("first").Link()); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	rec.WriteHeader(http.StatusOK)
+	values := rec.Result().Header.Values("Link")
+	if len(values) != 2 || values[0] != `</2>; rel=next, </9>; rel=last` || values[1] != `</1>; rel=first` {
+		t.Fatalf("Got %q\n", values)
+	}
+
+	rec = httptest.NewRecorder()
+	rec.Header().Set("Link", `</old>; rel=prev`)
+	webLinks.SetLinks(rec, nil)
+	if values := rec.Header().Values("Link"); len(values) != 0 {
+		t.Fatalf("Expected the header to be removed, got %q\n", values)
+	}
+}
+
+func TestSetLinksInvalid(t *testing.T) {
+	t.Parallel()
+	evil := webLinks.BuildLink("/x").Rel("next").Param("a=b, <evil>; rel", "x").Link()
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Link", `</old>; rel=prev`)
+	if err := webLinks.SetLinks(rec, webLinks.Links{evil}); !errors.Is(err, webLinks.ErrBadParamName) {
+		t.Fatalf("Expected ErrBadParamName, got %v\n", err)
+	}
+	if err := webLinks.AddLink(rec, evil); !errors.Is(err, webLinks.ErrBadParamName) {
+		t.Fatalf("Expected ErrBadParamName, got %v\n", err)
+	}
+	if values := rec.Header().Values("Link"); len(values) != 1 || values[0] != `</old>; rel=prev` {
+		t.Fatalf("Expected the header to be left alone, got %q\n", values)
+	}
+}
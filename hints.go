@@ -0,0 +1,169 @@
+package webLinks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptrace"
+	"net/textproto"
+	"strings"
+)
+
+// EarlyHints returns a copy of ctx that has requests made with it pass the
+// links of any 103 Early Hints response to them, as described in RFC 8297,
+// to hints, before the final response arrives. Clients can start fetching
+// the resources hinted at, like those of rel=preload and rel=preconnect
+// links, while the server is still preparing the response. See Hints to pick
+// those out. Any other 1xx responses are ignored.
+//
+// Relative targets are left as they are, and should be resolved against the
+// request's URL. hints is called from the goroutine reading the response,
+// and must not block for long. Any httptrace.ClientTrace already in ctx is
+// kept.
+func EarlyHints(ctx context.Context, hints func(Links)) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				hints(ParseHeader(http.Header(header)))
+			}
+			return nil
+		},
+	})
+}
+
+// hintRels are the relation types of resource hints, which have a client
+// fetch or connect to something ahead of time.
+var hintRels = []string{"preload", "preconnect", "dns-prefetch", "prefetch", "prerender", "modulepreload"}
+
+// Hints returns the links that are resource hints, as early hints hold: those
+// with any of the relation types preload, preconnect, dns-prefetch, prefetch,
+// prerender and modulepreload.
+func (l Links) Hints() Links {
+	return l.Filter(func(link Link) bool {
+		for _, rel := range hintRels {
+			if link.HasRel(rel) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// As returns the link's as param, the kind of resource a rel=preload link's
+// target is, such as "script", "style" or "font", lowercased, or "" if it has
+// none. Browsers ignore preloads of anything else than what as says.
+func (l Link) As() string {
+	param, _ := l.Param("as")
+	return strings.ToLower(param.Value)
+}
+
+// CrossOrigin returns the CORS mode the link's target is to be fetched in, as
+// given by its crossorigin param: "anonymous" or "use-credentials". Like
+// HTML's crossorigin attribute, a crossorigin param with no value, or any
+// other, means "anonymous". ok is false if the link has no crossorigin param,
+// so that its target is fetched without CORS.
+func (l Link) CrossOrigin() (mode string, ok bool) {
+	param, ok := l.Param("crossorigin")
+	if !ok {
+		return "", false
+	}
+	if strings.EqualFold(param.Value, "use-credentials") {
+		return "use-credentials", true
+	}
+	return "anonymous", true
+}
+
+// Integrity returns each of the hashes in the link's integrity param, the
+// subresource integrity metadata its target must match, like
+// "sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC".
+func (l Link) Integrity() []string {
+	param, _ := l.Param("integrity")
+	return strings.Fields(param.Value)
+}
+
+// NoPush reports whether the link has a nopush param, which asks servers
+// that push the targets of rel=preload links not to push this one's.
+func (l Link) NoPush() bool {
+	_, ok := l.Param("nopush")
+	return ok
+}
+
+// FetchPriority returns how the link's target is to be prioritized against
+// others of its kind, as given by its fetchpriority param: "high", "low" or
+// "auto". Links without one, or with any other value, get "auto".
+func (l Link) FetchPriority() string {
+	param, _ := l.Param("fetchpriority")
+	switch priority := strings.ToLower(param.Value); priority {
+	case "high", "low":
+		return priority
+	}
+	return "auto"
+}
+
+// A HintOption configures how WriteEarlyHints sends hints.
+type HintOption func(*hinter)
+
+type hinter struct {
+	push     bool
+	pushOpts *http.PushOptions
+}
+
+// Push has WriteEarlyHints also push the targets of rel=preload links with
+// opts, where w is an http.Pusher, as it is over HTTP/2, besides hinting at
+// them. Only targets that are paths on the same host, like "/style.css", can
+// be pushed, and links with a nopush param aren't.
+func Push(opts *http.PushOptions) HintOption {
+	return func(h *hinter) {
+		h.push, h.pushOpts = true, opts
+	}
+}
+
+// WriteEarlyHints adds the links that are resource hints, as Hints picks
+// them out, to the "Link" header of w, and writes a 103 Early Hints interim
+// response holding them, so that clients can start fetching what a page
+// needs while its handler is still preparing it. The links are still in the
+// header afterwards, and so sent with the final response too, as RFC 8297
+// suggests. Nothing is written if there are no hints.
+//
+// Links that aren't valid, as Links.Build checks, are an error, and have
+// nothing written. Errors pushing, other than http.ErrNotSupported when the
+// client has disabled push, are returned once every link has been tried.
+// WriteEarlyHints must be called before anything else of the response is
+// written.
+func WriteEarlyHints(w http.ResponseWriter, links Links, opts ...HintOption) error {
+	var h hinter
+	for _, opt := range opts {
+		opt(&h)
+	}
+	hints := links.Hints()
+	if len(hints) == 0 {
+		return nil
+	}
+	value, err := hints.Build()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	if pusher, ok := w.(http.Pusher); ok && h.push {
+		for _, link := range hints {
+			if !link.HasRel("preload") || !pushable(link) {
+				continue
+			}
+			if err := pusher.Push(link.URI, h.pushOpts); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	w.Header().Add("Link", value)
+	w.WriteHeader(http.StatusEarlyHints)
+	return errors.Join(errs...)
+}
+
+// pushable reports whether the link's target can be pushed: if it is a path
+// and the link has no nopush param.
+func pushable(link Link) bool {
+	if link.NoPush() {
+		return false
+	}
+	return len(link.URI) > 0 && link.URI[0] == '/' && (len(link.URI) == 1 || link.URI[1] != '/')
+}
@@ -0,0 +1,154 @@
+package webLinks_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestEarlyHints(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", `</style.css>; rel=preload; as=style, <https://cdn.example.com>; rel=preconnect`)
+		w.WriteHeader(http.StatusEarlyHints)
+		w.Header().Set("Link", `</next>; rel=next`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	var hints webLinks.Links
+	ctx := webLinks.EarlyHints(context.Background(), func(links webLinks.Links) {
+		hints = append(hints, links...)
+	})
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL, nil)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	resp.Body.Close()
+	if len(hints) != 2 || hints[0].URI != "/style.css" || !hints[1].HasRel("preconnect") {
+		t.Fatalf("Got hints %v\n", hints)
+	}
+	if links := webLinks.ParseHeader(resp.Header); len(links) != 1 || !links[0].HasRel("next") {
+		t.Fatalf("Got final links %v\n", links)
+	}
+}
+
+func TestLinksHints(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</a.js>; rel=modulepreload, </b>; rel=next, <//cdn.example.com>; rel="dns-prefetch preconnect", </c.css>; rel=PRELOAD`)
+	hints := links.Hints()
+	if len(hints) != 3 || hints[0].URI != "/a.js" || hints[2].URI != "/c.css" {
+		t.Fatalf("Got %v\n", hints)
+	}
+}
+
+func TestLinkHintAttributes(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</font.woff2>; rel=preload; as=Font; crossorigin; nopush; fetchpriority=HIGH; ` +
+		`integrity="sha256-abc sha384-def"`)[0]
+	if link.As() != "font" || link.FetchPriority() != "high" || !link.NoPush() {
+		t.Fatalf("Got as %q, fetchpriority %q and nopush %v\n", link.As(), link.FetchPriority(), link.NoPush())
+	}
+	if mode, ok := link.CrossOrigin(); !ok || mode != "anonymous" {
+		t.Fatalf("Got crossorigin %q, %v\n", mode, ok)
+	}
+	if integrity := link.Integrity(); len(integrity) != 2 || integrity[1] != "sha384-def" {
+		t.Fatalf("Got integrity %q\n", integrity)
+	}
+
+	link = webLinks.Parse(`</a.js>; rel=preload; crossorigin=use-credentials; fetchpriority=urgent`)[0]
+	if mode, _ := link.CrossOrigin(); mode != "use-credentials" || link.FetchPriority() != "auto" || link.NoPush() {
+		t.Fatalf("Got crossorigin %q, fetchpriority %q and nopush %v\n", mode, link.FetchPriority(), link.NoPush())
+	}
+	if _, ok := webLinks.Parse(`</a.js>; rel=preload`)[0].CrossOrigin(); ok {
+		t.Fatalf("Expected no crossorigin\n")
+	}
+}
+
+func TestBuildHintAttributes(t *testing.T) {
+	t.Parallel()
+	link, err := webLinks.BuildLink("/app.js").Rel("preload").As("script").CrossOrigin("anonymous").
+		Integrity("sha256-abc", "sha384-def").NoPush().FetchPriority("low").Build()
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	want := `</app.js>; rel=preload; as=script; crossorigin; fetchpriority=low; integrity="sha256-abc sha384-def"; nopush`
+	if link.String() != want {
+		t.Fatalf("Got %s\nexpected %s\n", link, want)
+	}
+	link = webLinks.BuildLink("/a").Rel("preload").CrossOrigin("use-credentials").Link()
+	if mode, _ := link.CrossOrigin(); mode != "use-credentials" {
+		t.Fatalf("Got crossorigin %q\n", mode)
+	}
+}
+
+// pushRecorder is an http.ResponseWriter that records what it's asked to
+// push, and the interim responses written.
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed  []string
+	interim []http.Header
+}
+
+func (w *pushRecorder) Push(target string, opts *http.PushOptions) error {
+	w.pushed = append(w.pushed, target)
+	if target == "/missing.js" {
+		return errors.New("push failed")
+	}
+	return nil
+}
+
+func (w *pushRecorder) WriteHeader(status int) {
+	if status == http.StatusEarlyHints {
+		w.interim = append(w.interim, w.Header().Clone())
+		return
+	}
+	w.ResponseRecorder.WriteHeader(status)
+}
+
+func TestWriteEarlyHints(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</style.css>; rel=preload; as=style, </next>; rel=next, <https://cdn.example.com>; rel=preconnect, ` +
+		`</app.js>; rel=preload; as=script; nopush, <//other.example.com/a.js>; rel=preload`)
+	w := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	if err := webLinks.WriteEarlyHints(w, links, webLinks.Push(nil)); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	if len(w.pushed) != 1 || w.pushed[0] != "/style.css" {
+		t.Fatalf("Got pushed %q\n", w.pushed)
+	}
+	if len(w.interim) != 1 {
+		t.Fatalf("Expected one interim response, got %d\n", len(w.interim))
+	}
+	hints := webLinks.ParseHeader(w.interim[0])
+	if len(hints) != 4 || !hints.Equal(links.Hints()) {
+		t.Fatalf("Got hints %v\n", hints)
+	}
+
+	w = &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	if err := webLinks.WriteEarlyHints(w, links); err != nil || len(w.pushed) != 0 {
+		t.Fatalf("Expected nothing pushed without Push, got %q, %v\n", w.pushed, err)
+	}
+}
+
+func TestWriteEarlyHintsErrors(t *testing.T) {
+	t.Parallel()
+	w := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	links := webLinks.Parse(`</missing.js>; rel=preload, </style.css>; rel=preload`)
+	if err := webLinks.WriteEarlyHints(w, links, webLinks.Push(nil)); err == nil || len(w.pushed) != 2 || len(w.interim) != 1 {
+		t.Fatalf("Expected the push to fail after trying both, got %v and %q\n", err, w.pushed)
+	}
+
+	w = &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	bad := webLinks.Links{webLinks.BuildLink("/x").Rel("preload").Param("a=b, <evil>; rel", "x").Link()}
+	if err := webLinks.WriteEarlyHints(w, bad); !errors.Is(err, webLinks.ErrBadParamName) || len(w.interim) != 0 {
+		t.Fatalf("Expected ErrBadParamName with nothing written, got %v\n", err)
+	}
+	if err := webLinks.WriteEarlyHints(w, webLinks.Parse(`</next>; rel=next`)); err != nil || len(w.interim) != 0 {
+		t.Fatalf("Expected nothing written without hints, got %v\n", err)
+	}
+}
@@ -0,0 +1,92 @@
+package webLinks
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// ParseHTML returns the links of r, an HTML document: those of its <link> and
+// <a> elements with both a rel and an href attribute, in document order, as
+// discovery mechanisms like Webmention's and WebSub's look for them besides
+// the Link header. Each of an element's other attributes, like type or
+// hreflang, becomes a param of its link, those without a value flags.
+//
+// Targets are resolved against base, or against the document's own
+// <base href> if it has one, itself resolved against base, as HTML resolves
+// them. base may be nil, leaving relative targets as they are unless the
+// document has a base of its own. Links are parsed from whatever of r could
+// be read, along with any error reading it.
+func ParseHTML(r io.Reader, base *url.URL) (Links, error) {
+	var links Links
+	var docBase *url.URL
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			err := z.Err()
+			if err == io.EOF {
+				err = nil
+			}
+			if docBase != nil {
+				base = docBase
+			}
+			if base != nil {
+				links = links.ResolveAll(base)
+			}
+			return links, err
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			switch t.DataAtom {
+			case atom.Base:
+				if href, ok := attr(t, "href"); ok && docBase == nil {
+					if ref, err := url.Parse(strings.TrimSpace(href)); err == nil {
+						docBase = ref
+						if base != nil {
+							docBase = base.ResolveReference(ref)
+						}
+					}
+				}
+			case atom.Link, atom.A:
+				if link, ok := htmlLink(t); ok {
+					link.Index = len(links)
+					links = append(links, link)
+				}
+			}
+		}
+	}
+}
+
+// attr returns the value of the attribute of t called name.
+func attr(t html.Token, name string) (string, bool) {
+	for _, a := range t.Attr {
+		if a.Namespace == "" && a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// htmlLink returns the link an element stands for, if it has both a rel and
+// an href.
+func htmlLink(t html.Token) (Link, bool) {
+	href, ok := attr(t, "href")
+	if _, hasRel := attr(t, "rel"); !ok || !hasRel {
+		return Link{}, false
+	}
+	// As HTML strips them from URLs
+	b := BuildLink(strings.Trim(href, " \t\n\f\r"))
+	for _, a := range t.Attr {
+		switch {
+		case a.Namespace != "" || a.Key == "href":
+		case a.Val == "":
+			b.Flag(a.Key)
+		default:
+			b.Param(a.Key, a.Val)
+		}
+	}
+	return b.Link(), true
+}
@@ -0,0 +1,58 @@
+package webLinks_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestParseHTML(t *testing.T) {
+	t.Parallel()
+	doc := `<!doctype html>
+<html><head>
+<link rel="stylesheet" href="/style.css" type="text/css" media=screen>
+<link rel=icon>
+<script>document.write('<link rel="webmention" href="/script">')</script>
+<!-- <link rel="webmention" href="/comment"> -->
+</head><body>
+<a href=" relative/page ">no rel</a>
+<a rel="author me" href=" relative/page " title="Ünïcode" hidden>me</a>
+<link rel=webmention href="">
+</body></html>`
+	base, _ := url.Parse("https://example.com/posts/1?x=y")
+	links, err := webLinks.ParseHTML(strings.NewReader(doc), base)
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	if len(links) != 3 {
+		t.Fatalf("Expected 3 links, got %v\n", links)
+	}
+	if links[0].URI != "https://example.com/style.css" || links[0].Type() != "text/css" || links[0].Media() != "screen" {
+		t.Fatalf("Got %v\n", links[0])
+	}
+	if links[1].URI != "https://example.com/posts/relative/page" || !links[1].HasRel("me") || links[1].Title() != "Ünïcode" {
+		t.Fatalf("Got %v\n", links[1])
+	}
+	if param, ok := links[1].Param("hidden"); !ok || !param.Flag {
+		t.Fatalf("Expected hidden to be a flag, got %+v\n", links[1].Params)
+	}
+	if links[2].URI != "https://example.com/posts/1?x=y" || links[2].Index != 2 {
+		t.Fatalf("Expected an empty href to be the page itself, got %v\n", links[2])
+	}
+}
+
+func TestParseHTMLBase(t *testing.T) {
+	t.Parallel()
+	doc := `<link rel=next href="page/2"><base href="/docs/">`
+	base, _ := url.Parse("https://example.com/a/b")
+	links, err := webLinks.ParseHTML(strings.NewReader(doc), base)
+	if err != nil || len(links) != 1 || links[0].URI != "https://example.com/docs/page/2" {
+		t.Fatalf("Got %v, %v\n", links, err)
+	}
+	links, _ = webLinks.ParseHTML(strings.NewReader(`<link rel=next href="page/2">`), nil)
+	if len(links) != 1 || links[0].URI != "page/2" {
+		t.Fatalf("Expected the target to be left relative, got %v\n", links)
+	}
+}
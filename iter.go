@@ -0,0 +1,74 @@
+//go:build go1.23
+
+package webLinks
+
+import (
+	"context"
+	"io"
+	"iter"
+	"net/http"
+)
+
+// All returns an iterator over the links, in order.
+func (l Links) All() iter.Seq[Link] {
+	return func(yield func(Link) bool) {
+		for _, link := range l {
+			if !yield(link) {
+				return
+			}
+		}
+	}
+}
+
+// ByRel returns an iterator over the links along with each of their relation
+// types, in order. A link whose rel param holds several relation types, like
+// rel="next last", is yielded once for each of them. Links without a rel
+// param are skipped.
+func (l Links) ByRel() iter.Seq2[string, Link] {
+	return func(yield func(string, Link) bool) {
+		for _, link := range l {
+			for _, rel := range link.relTypes() {
+				if !yield(rel, link) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Pages returns an iterator over the pages of a paginated collection, fetched
+// as a Paginator configured by opts does, starting with req, made with ctx,
+// and using client, or http.DefaultClient if client is nil:
+//
+//	for resp, err := range webLinks.Pages(ctx, nil, req) {
+//		if err != nil {
+//			return err
+//		}
+//		// read resp.Body
+//	}
+//
+// The body of each response is closed once the loop is done with it, so it
+// mustn't be kept for later. Fetching stops when the loop breaks, after a
+// page without a next link, or once fetching a page fails, which is yielded
+// as an error.
+func Pages(ctx context.Context, client *http.Client, req *http.Request, opts ...PageOption) iter.Seq2[*http.Response, error] {
+	return func(yield func(*http.Response, error) bool) {
+		p := NewPaginator(client, req.WithContext(ctx), opts...)
+		defer p.Close()
+		for {
+			resp, err := p.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			more := yield(resp, nil)
+			resp.Body.Close()
+			if !more {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+//go:build go1.23
+
+package webLinks_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestLinksAll(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</1>; rel=prev, </3>; rel=next, </9>; rel=last`)
+	var uris []string
+	for link := range links.All() {
+		uris = append(uris, link.URI)
+		if link.URI == "/3" {
+			break
+		}
+	}
+	if len(uris) != 2 || uris[0] != "/1" || uris[1] != "/3" {
+		t.Fatalf("Got %q\n", uris)
+	}
+}
+
+func TestLinksByRel(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</1>; rel="first prev", </none>; title=x, </3>; rel=next`)
+	expected := []struct{ rel, uri string }{{"first", "/1"}, {"prev", "/1"}, {"next", "/3"}}
+	i := 0
+	for rel, link := range links.ByRel() {
+		if i >= len(expected) || rel != expected[i].rel || link.URI != expected[i].uri {
+			t.Fatalf("Got %q %q at %d\n", rel, link.URI, i)
+		}
+		i++
+	}
+	if i != len(expected) {
+		t.Fatalf("Got %d pairs expected %d\n", i, len(expected))
+	}
+	for rel := range links.ByRel() {
+		if rel != "first" {
+			t.Fatalf("Got %q expected first\n", rel)
+		}
+		break
+	}
+}
+
+func TestPages(t *testing.T) {
+	t.Parallel()
+	srv, requests := pagedServer(t, 4)
+	req, _ := http.NewRequest("GET", srv.URL+"/items", nil)
+	var bodies []string
+	for resp, err := range webLinks.Pages(context.Background(), srv.Client(), req) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		bodies = append(bodies, string(body))
+	}
+	if len(bodies) != 4 || bodies[3] != "page 4" {
+		t.Fatalf("Got %q\n", bodies)
+	}
+
+	*requests = nil
+	for resp := range webLinks.Pages(context.Background(), srv.Client(), req) {
+		if resp.Request.URL.Query().Get("page") == "2" {
+			break
+		}
+	}
+	if len(*requests) != 2 {
+		t.Fatalf("Expected fetching to stop on break, got %d requests\n", len(*requests))
+	}
+}
+
+func TestPagesCanceled(t *testing.T) {
+	t.Parallel()
+	srv, _ := pagedServer(t, 4)
+	req, _ := http.NewRequest("GET", srv.URL+"/items", nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var errs []error
+	for resp, err := range webLinks.Pages(ctx, srv.Client(), req) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if resp.Request.URL.Query().Get("page") == "2" {
+			cancel()
+		}
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+		t.Fatalf("Expected a single cancellation error, got %v\n", errs)
+	}
+}
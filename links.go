@@ -0,0 +1,590 @@
+package webLinks
+
+import (
+	"sort"
+	"strings"
+)
+
+// Add appends links to l.
+func (l *Links) Add(links ...Link) {
+	*l = append(*l, links...)
+}
+
+// Remove removes the relation type rel from every link in l. Links left
+// without any relation type are removed altogether, while those that have
+// others, like a rel="next last" link when removing "next", are kept with
+// those. It returns the number of links rel was removed from.
+//
+// Links are never modified in place: those that lose rel are copied first,
+// so other holders of their Params don't see the change.
+func (l *Links) Remove(rel string) int {
+	_, n := l.remove(rel)
+	return n
+}
+
+// remove is Remove, also returning the index at which the first link rel was
+// removed from was, or -1.
+func (l *Links) remove(rel string) (first, n int) {
+	first = -1
+	kept := (*l)[:0]
+	for _, link := range *l {
+		rels := link.relTypes()
+		others := make([]string, 0, len(rels))
+		for _, r := range rels {
+			if !sameRel(r, rel) {
+				others = append(others, r)
+			}
+		}
+		if len(others) == len(rels) {
+			kept = append(kept, link)
+			continue
+		}
+		if n++; first == -1 {
+			first = len(kept)
+		}
+		if len(others) > 0 {
+			kept = append(kept, link.withParam("rel", strings.Join(others, " ")))
+		}
+	}
+	// Don't hold on to the links that were removed
+	for i := len(kept); i < len(*l); i++ {
+		(*l)[i] = Link{}
+	}
+	*l = kept
+	return first, n
+}
+
+// ReplaceRel replaces the links with the relation type rel by link, in a
+// single step: rel is removed from every link as Remove does, and link takes
+// the place of the first one it was removed from, or is appended if there
+// were none. link would usually have rel among its own relation types.
+func (l *Links) ReplaceRel(rel string, link Link) {
+	first, _ := l.remove(rel)
+	if first == -1 {
+		l.Add(link)
+		return
+	}
+	*l = append(*l, Link{})
+	copy((*l)[first+1:], (*l)[first:])
+	(*l)[first] = link
+}
+
+// withParam returns a copy of the link whose param called name, ignoring
+// case, holds value instead, made afresh as LinkBuilder.Param makes it, so
+// that nothing of how the old value appeared in the header is kept. Any other
+// occurrences of the param are dropped.
+func (l Link) withParam(name, value string) Link {
+	params := make(map[string]Param, len(l.Params))
+	for key, param := range l.Params {
+		if strings.EqualFold(key, name) {
+			name = key
+		}
+		params[key] = param
+	}
+	param := plainParam(value)
+	params[name] = param
+	l.Params = params
+
+	ordered := make([]LinkParam, 0, len(l.Ordered))
+	replaced := false
+	for _, p := range l.Ordered {
+		if p.Name == name {
+			if replaced {
+				continue
+			}
+			p.Param, replaced = param, true
+		}
+		ordered = append(ordered, p)
+	}
+	l.Ordered = ordered
+	if _, ok := l.Duplicates[name]; ok {
+		duplicates := make(map[string][]Param, len(l.Duplicates))
+		for key, params := range l.Duplicates {
+			if key != name {
+				duplicates[key] = params
+			}
+		}
+		l.Duplicates = duplicates
+	}
+	return l
+}
+
+// sameRel reports whether a and b are the same relation type: registered
+// types are compared case-insensitively, as RFC 8288, section 2.1.1
+// requires, and extension types, being URIs, as they are.
+func sameRel(a, b string) bool {
+	if isRegisteredRel(a) {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// Filter returns the links for which keep returns true, in order.
+func (l Links) Filter(keep func(Link) bool) Links {
+	var kept Links
+	for _, link := range l {
+		if keep(link) {
+			kept = append(kept, link)
+		}
+	}
+	return kept
+}
+
+// Rel returns every link that has the relation type rel, in order. A rel
+// param may hold several relation types, like rel="alternate stylesheet",
+// and the link is returned for any of them. Registered relation types are
+// matched case-insensitively.
+func (l Links) Rel(rel string) Links {
+	return l.Filter(func(link Link) bool {
+		return link.HasRel(rel)
+	})
+}
+
+// First returns the first link that has the relation type rel, matched as
+// Rel does. ok is false if there is none.
+func (l Links) First(rel string) (link Link, ok bool) {
+	for _, link := range l {
+		if link.HasRel(rel) {
+			return link, true
+		}
+	}
+	return Link{}, false
+}
+
+// MapAll returns every link by each of its relation types, in order, unlike
+// Map, which only keeps one. Registered relation types are lowercased, so
+// that links with rel=Next and rel=next end up together. Links without a rel
+// param are omitted.
+func (l Links) MapAll() map[string]Links {
+	all := make(map[string]Links, len(l))
+	for _, link := range l {
+		var seen []string
+		for _, rel := range link.relTypes() {
+			rel = normalRel(rel)
+			if !contains(seen, rel) {
+				seen = append(seen, rel)
+				all[rel] = append(all[rel], link)
+			}
+		}
+	}
+	return all
+}
+
+// Sort sorts the links in place, by less, keeping links that are equal to
+// each other in the order they were in. LessRel, LessURI and LessOrder are
+// the usual choices for less.
+func (l Links) Sort(less func(a, b Link) bool) {
+	sort.SliceStable(l, func(i, j int) bool {
+		return less(l[i], l[j])
+	})
+}
+
+// LessRel orders links by their relation types, lowercased if registered,
+// and compared in turn. Links without a rel come first.
+func LessRel(a, b Link) bool {
+	ra, rb := a.relTypes(), b.relTypes()
+	for i := 0; i < len(ra) && i < len(rb); i++ {
+		x, y := normalRel(ra[i]), normalRel(rb[i])
+		if x != y {
+			return x < y
+		}
+	}
+	return len(ra) < len(rb)
+}
+
+// normalRel lowercases rel if it is a registered relation type, which are
+// compared case-insensitively.
+func normalRel(rel string) string {
+	if isRegisteredRel(rel) {
+		return strings.ToLower(rel)
+	}
+	return rel
+}
+
+// LessURI orders links by their targets, as they are.
+func LessURI(a, b Link) bool {
+	return a.URI < b.URI
+}
+
+// LessOrder orders links by their Index, restoring the order they were
+// parsed in.
+func LessOrder(a, b Link) bool {
+	return a.Index < b.Index
+}
+
+// Dedupe returns the links with duplicates removed, keeping the first of
+// each. Links are duplicates if their targets and anchors are equivalent
+// once normalized as described in RFC 3986, section 6.2.2, and they have the
+// same relation types, whatever their order or the case of registered ones.
+// Their other params aren't compared.
+func (l Links) Dedupe() Links {
+	var kept Links
+	seen := make(map[string]bool, len(l))
+	for _, link := range l {
+		key := link.dedupeKey()
+		if !seen[key] {
+			seen[key] = true
+			kept = append(kept, link)
+		}
+	}
+	return kept
+}
+
+// dedupeKey returns what tells the link apart from those Dedupe considers to
+// be its duplicates.
+func (l Link) dedupeKey() string {
+	key := normalURI(l.URI) + " " + l.relSet()
+	if anchor, ok := l.Anchor(); ok {
+		key += " " + normalURI(anchor)
+	}
+	return key
+}
+
+// Conflict says what Merge does with links that are in both of the sets it
+// merges.
+type Conflict int
+
+const (
+	// PreferFirst keeps the link from the first set, dropping the other.
+	PreferFirst Conflict = iota
+	// PreferLast puts the link from the second set in place of the first.
+	PreferLast
+	// KeepBoth keeps both links.
+	KeepBoth
+)
+
+// MergeOption configures Merge.
+type MergeOption func(*merge)
+
+type merge struct {
+	conflict Conflict
+	key      func(Link) string
+}
+
+// OnConflict sets what Merge does with links that are in both sets. The
+// default is PreferFirst.
+func OnConflict(c Conflict) MergeOption {
+	return func(m *merge) {
+		m.conflict = c
+	}
+}
+
+// MergeKey sets how Merge tells that links are the same: those for which key
+// returns the same string are. By default links are the same if they would be
+// duplicates to Dedupe, having the same target, anchor and relation types.
+// MergeKey(func(l Link) string { return l.Params["rel"].Value }), for
+// instance, makes links with the same relation types conflict, whatever
+// their targets.
+func MergeKey(key func(Link) string) MergeOption {
+	return func(m *merge) {
+		m.key = key
+	}
+}
+
+// Merge returns the links in a followed by those in b, unifying those that are
+// in both as configured by opts. Links within either set aren't unified with
+// each other; see Dedupe for that.
+func Merge(a, b Links, opts ...MergeOption) Links {
+	m := merge{key: Link.dedupeKey}
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	merged := make(Links, len(a), len(a)+len(b))
+	copy(merged, a)
+	indexes := make(map[string]int, len(a))
+	for i, link := range a {
+		key := m.key(link)
+		if _, ok := indexes[key]; !ok {
+			indexes[key] = i
+		}
+	}
+	for _, link := range b {
+		i, ok := indexes[m.key(link)]
+		switch {
+		case !ok || m.conflict == KeepBoth:
+			merged = append(merged, link)
+		case m.conflict == PreferLast:
+			merged[i] = link
+		}
+	}
+	return merged
+}
+
+// relSet returns the link's relation types, normalized, sorted and without
+// repeats, so that links with the same set of them have the same one.
+func (l Link) relSet() string {
+	var rels []string
+	for _, rel := range l.relTypes() {
+		if rel = normalRel(rel); !contains(rels, rel) {
+			rels = append(rels, rel)
+		}
+	}
+	sort.Strings(rels)
+	return strings.Join(rels, " ")
+}
+
+// Equal reports whether the links are the same, whatever their order: each
+// link in l must be Equal to a link in other, which isn't matched with any
+// other link in l.
+func (l Links) Equal(other Links) bool {
+	if len(l) != len(other) {
+		return false
+	}
+	matched := make([]bool, len(other))
+outer:
+	for _, link := range l {
+		for i, o := range other {
+			if !matched[i] && link.Equal(o) {
+				matched[i] = true
+				continue outer
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Equal reports whether the links mean the same thing: their targets are
+// equivalent once normalized as Dedupe does, and so are their params. Param
+// names are compared case-insensitively, rel values as sets of relation types,
+// and params that appeared more than once with every occurrence, in order,
+// as Values returns them. How values were quoted or escaped, and the order
+// and spelling of param names, don't matter.
+func (l Link) Equal(other Link) bool {
+	if normalURI(l.URI) != normalURI(other.URI) || len(l.Params) != len(other.Params) {
+		return false
+	}
+	for name := range l.Params {
+		if _, ok := other.Param(name); !ok {
+			return false
+		}
+		if strings.EqualFold(name, "rel") {
+			if l.relSet() != other.relSet() {
+				return false
+			}
+			continue
+		}
+		a, b := l.values(name), other.values(name)
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !a[i].equal(b[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// values is Values, ignoring the case of name.
+func (l Link) values(name string) []Param {
+	for key := range l.Params {
+		if strings.EqualFold(key, name) {
+			return l.Values(key)
+		}
+	}
+	return nil
+}
+
+// equal reports whether the params have the same value, in the same charset
+// and language, ignoring their case.
+func (p Param) equal(other Param) bool {
+	return p.Value == other.Value && p.Flag == other.Flag &&
+		strings.EqualFold(p.Enc, other.Enc) && strings.EqualFold(p.Lang, other.Lang)
+}
+
+// Clone returns a deep copy of the link, sharing none of its Params,
+// Duplicates or Ordered with it, so that either can be modified safely.
+func (l Link) Clone() Link {
+	if l.Params != nil {
+		params := make(map[string]Param, len(l.Params))
+		for name, param := range l.Params {
+			params[name] = param
+		}
+		l.Params = params
+	}
+	if l.Duplicates != nil {
+		duplicates := make(map[string][]Param, len(l.Duplicates))
+		for name, params := range l.Duplicates {
+			duplicates[name] = append([]Param(nil), params...)
+		}
+		l.Duplicates = duplicates
+	}
+	if l.Ordered != nil {
+		l.Ordered = append([]LinkParam(nil), l.Ordered...)
+	}
+	return l
+}
+
+// Clone returns a deep copy of the links, each copied as Link.Clone does.
+func (l Links) Clone() Links {
+	if l == nil {
+		return nil
+	}
+	links := make(Links, len(l))
+	for i, link := range l {
+		links[i] = link.Clone()
+	}
+	return links
+}
+
+// GroupBy returns the links by the value of their param called name, matched
+// ignoring case, in order. Links with several values for it, like alternates
+// in several languages, are grouped under each of them; links without it are
+// omitted. See MapAll for grouping by relation type, as rel values may hold
+// several.
+func (l Links) GroupBy(name string) map[string]Links {
+	groups := map[string]Links{}
+	for _, link := range l {
+		var seen []string
+		for _, param := range link.values(name) {
+			if !contains(seen, param.Value) {
+				seen = append(seen, param.Value)
+				groups[param.Value] = append(groups[param.Value], link)
+			}
+		}
+	}
+	return groups
+}
+
+// A LinkDiff describes how one set of links differs from another, as Diff
+// finds.
+type LinkDiff struct {
+	Added   Links        // links only in the second set
+	Removed Links        // links only in the first set
+	Changed []LinkChange // links in both, with params that differ
+}
+
+// A LinkChange is a link that is in both of the sets Diff compares, but
+// isn't Equal in them.
+type LinkChange struct {
+	Old, New Link
+}
+
+// Empty reports whether the diff found no differences at all.
+func (d LinkDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares the links in before with those in after. Links are the same
+// link in both if they would be duplicates to Dedupe, having equivalent
+// targets and anchors and the same relation types; those that appear several
+// times in a set are paired up in order. Links in both are changed if they
+// aren't Equal, as when their titles differ. Added and Changed are in the
+// order of after, Removed in that of before.
+func Diff(before, after Links) LinkDiff {
+	unmatched := make(map[string][]int, len(before))
+	for i, link := range before {
+		key := link.dedupeKey()
+		unmatched[key] = append(unmatched[key], i)
+	}
+	matched := make([]bool, len(before))
+	var d LinkDiff
+	for _, link := range after {
+		key := link.dedupeKey()
+		indexes := unmatched[key]
+		if len(indexes) == 0 {
+			d.Added = append(d.Added, link)
+			continue
+		}
+		unmatched[key] = indexes[1:]
+		matched[indexes[0]] = true
+		if old := before[indexes[0]]; !old.Equal(link) {
+			d.Changed = append(d.Changed, LinkChange{Old: old, New: link})
+		}
+	}
+	for i, link := range before {
+		if !matched[i] {
+			d.Removed = append(d.Removed, link)
+		}
+	}
+	return d
+}
+
+// Matches reports whether the link has everything pattern has, ignoring
+// whatever else it has: the same target, unless pattern's is empty, every
+// relation type of pattern's rel, and every other param of pattern's, with
+// the same value, or with any value at all if pattern's is a flag. Targets
+// are compared once normalized, as Dedupe does, and param names ignoring
+// case. A param that appeared more than once matches any of its values.
+//
+// Patterns are easily built with BuildLink, which leaves the target empty if
+// given "":
+//
+//	links.ContainsMatch(BuildLink("").Rel("next").Param("per_page", "50").Link())
+func (l Link) Matches(pattern Link) bool {
+	if pattern.URI != "" && normalURI(l.URI) != normalURI(pattern.URI) {
+		return false
+	}
+	for name, param := range pattern.Params {
+		if strings.EqualFold(name, "rel") {
+			for _, rel := range strings.Fields(param.Value) {
+				if !l.HasRel(rel) {
+					return false
+				}
+			}
+			continue
+		}
+		values := l.values(name)
+		if len(values) == 0 {
+			return false
+		}
+		if param.Flag {
+			continue
+		}
+		found := false
+		for _, value := range values {
+			if value.Value == param.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsMatch reports whether any of the links Matches pattern.
+func (l Links) ContainsMatch(pattern Link) bool {
+	for _, link := range l {
+		if link.Matches(pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesSubset reports whether every link in patterns is matched, as
+// ContainsMatch does, by one of the links in l, whatever else l holds. Each
+// pattern must be matched by a different link, so that two patterns for
+// rel=alternate need two alternates.
+func (l Links) MatchesSubset(patterns Links) bool {
+	// Patterns are paired with links by finding augmenting paths, as in
+	// bipartite matching, so that a pattern taking a link that another
+	// needs more can move on to some other link
+	matchedBy := make([]int, len(l)) // the pattern matching each link, plus one
+	var assign func(pattern int, seen []bool) bool
+	assign = func(pattern int, seen []bool) bool {
+		for i, link := range l {
+			if seen[i] || !link.Matches(patterns[pattern]) {
+				continue
+			}
+			seen[i] = true
+			if matchedBy[i] == 0 || assign(matchedBy[i]-1, seen) {
+				matchedBy[i] = pattern + 1
+				return true
+			}
+		}
+		return false
+	}
+	for pattern := range patterns {
+		if !assign(pattern, make([]bool, len(l))) {
+			return false
+		}
+	}
+	return true
+}
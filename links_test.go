@@ -0,0 +1,436 @@
+package webLinks_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestLinksAdd(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</a>; rel=next`)
+	links.Add(webLinks.BuildLink("/self").Rel("self").Link(), webLinks.BuildLink("/b").Rel("prev").Link())
+	if expected := `</a>; rel=next, </self>; rel=self, </b>; rel=prev`; links.String() != expected {
+		t.Fatalf("Got %s expected %s\n", links, expected)
+	}
+}
+
+var removeTests = []struct {
+	input   string
+	rel     string
+	removed int
+	output  string
+}{
+	{`</a>; rel=next, </b>; rel=prev`, "next", 1, `</b>; rel=prev`},
+	{`</a>; rel=next, </b>; rel=prev`, "last", 0, `</a>; rel=next, </b>; rel=prev`},
+	{`</a>; rel=next, </b>; rel=Next, </c>; rel=prev`, "NEXT", 2, `</c>; rel=prev`},
+	{`</a>; rel="next last", </b>; rel=prev`, "next", 1, `</a>; rel=last, </b>; rel=prev`},
+	{`</a>; rel="http://example.com/Rel", </b>; rel="http://example.com/rel"`, "http://example.com/rel", 1, `</a>; rel="http://example.com/Rel"`},
+	{`</a>; title=none, </b>; rel=prev`, "prev", 1, `</a>; title=none`},
+}
+
+func TestLinksRemove(t *testing.T) {
+	t.Parallel()
+	for _, test := range removeTests {
+		links := webLinks.Parse(test.input)
+		if removed := links.Remove(test.rel); removed != test.removed {
+			t.Fatalf("Removed %d expected %d from %s\n", removed, test.removed, test.input)
+		}
+		if links.String() != test.output {
+			t.Fatalf("Got %s expected %s\n", links, test.output)
+		}
+	}
+}
+
+func TestLinksRemoveCopies(t *testing.T) {
+	t.Parallel()
+	original := webLinks.Parse(`</a>; rel="next last"`)
+	links := append(webLinks.Links{}, original...)
+	links.Remove("last")
+	if rel := original[0].Params["rel"].Value; rel != "next last" {
+		t.Fatalf("Expected the original link to be left alone, got rel %q\n", rel)
+	}
+	if rel := links[0].Ordered[0].Value; rel != "next" {
+		t.Fatalf("Expected Ordered to follow, got %q\n", rel)
+	}
+}
+
+func TestLinksRemoveFormatted(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</a>;rel="next  last"`, webLinks.WithLossless())
+	links.Remove("last")
+	if raw := links[0].Params["rel"].Raw; raw != "" {
+		t.Fatalf("Expected the old rel to be forgotten, got %q\n", raw)
+	}
+	if output := links.String(); output != `</a>; rel=next` {
+		t.Fatalf("Got %s\n", output)
+	}
+}
+
+var replaceTests = []struct {
+	input  string
+	rel    string
+	link   webLinks.Link
+	output string
+}{
+	{`</a>; rel=prev, </b>; rel=next, </c>; rel=last`, "next", webLinks.BuildLink("/z").Rel("next").Link(), `</a>; rel=prev, </z>; rel=next, </c>; rel=last`},
+	{`</a>; rel=prev`, "next", webLinks.BuildLink("/z").Rel("next").Link(), `</a>; rel=prev, </z>; rel=next`},
+	{`</a>; rel="next last", </b>; rel=next`, "next", webLinks.BuildLink("/z").Rel("next").Link(), `</z>; rel=next, </a>; rel=last`},
+	{`</b>; rel=next, </a>; rel=next`, "next", webLinks.BuildLink("/z").Rel("next").Link(), `</z>; rel=next`},
+}
+
+func TestLinksReplaceRel(t *testing.T) {
+	t.Parallel()
+	for _, test := range replaceTests {
+		links := webLinks.Parse(test.input)
+		links.ReplaceRel(test.rel, test.link)
+		if links.String() != test.output {
+			t.Fatalf("Got %s expected %s\n", links, test.output)
+		}
+	}
+}
+
+func TestLinksFilter(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</a.png>; rel=icon; type="image/png", </a.html>; rel=alternate; type="text/html", </a.svg>; rel=icon; type="image/svg+xml"`)
+	images := links.Filter(func(link webLinks.Link) bool {
+		mediatype, _, _ := link.MediaType()
+		return strings.HasPrefix(mediatype, "image/")
+	})
+	if len(images) != 2 || images[0].URI != "/a.png" || images[1].URI != "/a.svg" {
+		t.Fatalf("Got %s\n", images)
+	}
+	if none := links.Filter(func(webLinks.Link) bool { return false }); len(none) != 0 {
+		t.Fatalf("Got %s\n", none)
+	}
+}
+
+func TestLinksRel(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</en>; rel=alternate; hreflang=en, </style>; rel="Alternate stylesheet", </next>; rel=next, </de>; rel=alternate; hreflang=de`)
+	alternates := links.Rel("alternate")
+	if len(alternates) != 3 || alternates[0].URI != "/en" || alternates[1].URI != "/style" || alternates[2].URI != "/de" {
+		t.Fatalf("Got %s\n", alternates)
+	}
+	if none := links.Rel("prev"); len(none) != 0 {
+		t.Fatalf("Got %s\n", none)
+	}
+}
+
+func TestLinksFirst(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</1>; rel=prev, </3>; rel="next last", </4>; rel=next`)
+	if link, ok := links.First("next"); !ok || link.URI != "/3" {
+		t.Fatalf("Got %s %v expected </3>\n", link, ok)
+	}
+	if link, ok := links.First("LAST"); !ok || link.URI != "/3" {
+		t.Fatalf("Got %s %v expected </3>\n", link, ok)
+	}
+	if link, ok := links.First("first"); ok || link.URI != "" {
+		t.Fatalf("Got %s %v expected nothing\n", link, ok)
+	}
+}
+
+func BenchmarkLinksFirst(b *testing.B) {
+	links := webLinks.Parse(`</1>; rel=first, </2>; rel=prev, </4>; rel=next, </9>; rel=last`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		links.First("next")
+	}
+}
+
+func TestLinksMapAll(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</en>; rel=alternate, </style>; rel="Alternate stylesheet", </next>; rel="next next", </de>; rel=alternate, </none>; title=x`)
+	all := links.MapAll()
+	expected := map[string][]string{
+		"alternate":  {"/en", "/style", "/de"},
+		"stylesheet": {"/style"},
+		"next":       {"/next"},
+	}
+	if len(all) != len(expected) {
+		t.Fatalf("Got %v\n", all)
+	}
+	for rel, uris := range expected {
+		if len(all[rel]) != len(uris) {
+			t.Fatalf("Got %s for %q expected %v\n", all[rel], rel, uris)
+		}
+		for i, uri := range uris {
+			if all[rel][i].URI != uri {
+				t.Fatalf("Got %s for %q expected %v\n", all[rel], rel, uris)
+			}
+		}
+	}
+}
+
+func TestLinksSort(t *testing.T) {
+	t.Parallel()
+	links := webLinks.ParseMultiple([]string{`</c>; rel=prev, </a>; rel=Next`, `</b>; rel="next last", </d>; title=x`})
+	uris := func() string {
+		var uris []string
+		for _, link := range links {
+			uris = append(uris, link.URI)
+		}
+		return strings.Join(uris, " ")
+	}
+	for i, link := range links {
+		if link.Index != i {
+			t.Fatalf("Got index %d expected %d for %s\n", link.Index, i, link)
+		}
+	}
+
+	links.Sort(webLinks.LessURI)
+	if uris() != "/a /b /c /d" {
+		t.Fatalf("Got %s\n", uris())
+	}
+	links.Sort(webLinks.LessRel)
+	if uris() != "/d /a /b /c" {
+		t.Fatalf("Got %s\n", uris())
+	}
+	links.Sort(webLinks.LessOrder)
+	if uris() != "/c /a /b /d" {
+		t.Fatalf("Got %s\n", uris())
+	}
+}
+
+func TestLinksDedupe(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`<http://Example.COM:80/a/./b/../c>; rel="next last", ` +
+		`<HTTP://example.com/a/c>; rel="Last next"; title=dup, ` +
+		`<http://example.com/a/%63>; rel="next last next", ` +
+		`<http://example.com/a/c>; rel=next, ` +
+		`<https://example.com:443>; rel=home, ` +
+		`<https://example.com/>; rel=home, ` +
+		`<https://example.com/>; rel=home; anchor="#x", ` +
+		`<https://example.com:8443/>; rel=home, ` +
+		`<http://example.com/a%2Fb>; rel=up, ` +
+		`<http://example.com/a/b>; rel=up, ` +
+		`<../x>; rel=up, ` +
+		`<../x>; rel=up`)
+	deduped := links.Dedupe()
+	expected := []string{
+		"http://Example.COM:80/a/./b/../c",
+		"http://example.com/a/c",
+		"https://example.com:443",
+		"https://example.com/",
+		"https://example.com:8443/",
+		"http://example.com/a%2Fb",
+		"http://example.com/a/b",
+		"../x",
+	}
+	if len(deduped) != len(expected) {
+		t.Fatalf("Got %s\n", deduped)
+	}
+	for i, link := range deduped {
+		if link.URI != expected[i] {
+			t.Fatalf("Got %q expected %q at %d\n", link.URI, expected[i], i)
+		}
+	}
+}
+
+var mergeTests = []struct {
+	opts   []webLinks.MergeOption
+	output string
+}{
+	{nil, `</feed>; rel=alternate; title=header, </next>; rel=next, </hub>; rel=hub, </other>; rel=next`},
+	{[]webLinks.MergeOption{webLinks.OnConflict(webLinks.PreferFirst)}, `</feed>; rel=alternate; title=header, </next>; rel=next, </hub>; rel=hub, </other>; rel=next`},
+	{[]webLinks.MergeOption{webLinks.OnConflict(webLinks.PreferLast)}, `</feed>; rel=Alternate; title=body, </next>; rel=next, </hub>; rel=hub, </other>; rel=next`},
+	{[]webLinks.MergeOption{webLinks.OnConflict(webLinks.KeepBoth)}, `</feed>; rel=alternate; title=header, </next>; rel=next, </feed>; rel=Alternate; title=body, </hub>; rel=hub, </other>; rel=next`},
+	{
+		[]webLinks.MergeOption{webLinks.OnConflict(webLinks.PreferLast), webLinks.MergeKey(func(l webLinks.Link) string { return l.Params["rel"].Value })},
+		`</feed>; rel=alternate; title=header, </other>; rel=next, </feed>; rel=Alternate; title=body, </hub>; rel=hub`,
+	},
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	header := webLinks.Parse(`</feed>; rel=alternate; title=header, </next>; rel=next`)
+	body := webLinks.Parse(`</feed>; rel=Alternate; title=body, </hub>; rel=hub, </other>; rel=next`)
+	for _, test := range mergeTests {
+		if merged := webLinks.Merge(header, body, test.opts...); merged.String() != test.output {
+			t.Fatalf("Got %s expected %s\n", merged, test.output)
+		}
+	}
+	if header.String() != `</feed>; rel=alternate; title=header, </next>; rel=next` {
+		t.Fatalf("Merge modified its input: %s\n", header)
+	}
+}
+
+var equalTests = []struct {
+	a, b  string
+	equal bool
+}{
+	{`</a>; rel=next`, `</a>; rel=next`, true},
+	{`</a>; rel=next; title="Page"`, `</a>; title=Page; rel="next"`, true},
+	{`</a>; rel="next last"`, `</a>; rel="Last  next"`, true},
+	{`</a>; rel=next; title="a\"b"`, `</a>; rel=next; title="a\"\b"`, true},
+	{`<http://Example.com:80/a>; rel=next`, `<http://example.com/a>; rel=next`, true},
+	{`</a>; rel=next; title*=UTF-8'DE'x`, `</a>; rel=next; title*=utf-8'de'x`, true},
+	{`</a>; rel=alternate; hreflang=de; hreflang=en`, `</a>; rel=alternate; hreflang=de; hreflang=en`, true},
+	{`</a>; rel=next`, `</b>; rel=next`, false},
+	{`</a>; rel=next`, `</a>; rel=prev`, false},
+	{`</a>; rel=next`, `</a>; rel="next last"`, false},
+	{`</a>; rel=next; title=a`, `</a>; rel=next; title=b`, false},
+	{`</a>; rel=next; title=a`, `</a>; rel=next; type=a`, false},
+	{`</a>; rel=next; title=a`, `</a>; rel=next`, false},
+	{`</a>; rel=next; foo`, `</a>; rel=next; foo=""`, false},
+	{`</a>; rel=next; title=x`, `</a>; rel=next; title*=UTF-8''x`, false},
+	{`</a>; rel=alternate; hreflang=de; hreflang=en`, `</a>; rel=alternate; hreflang=en`, false},
+}
+
+func TestLinkEqual(t *testing.T) {
+	t.Parallel()
+	for _, test := range equalTests {
+		a, b := webLinks.Parse(test.a)[0], webLinks.Parse(test.b)[0]
+		if equal := a.Equal(b); equal != test.equal {
+			t.Fatalf("Got %v expected %v for %s and %s\n", equal, test.equal, test.a, test.b)
+		}
+		if equal := b.Equal(a); equal != test.equal {
+			t.Fatalf("Got %v expected %v for %s and %s\n", equal, test.equal, test.b, test.a)
+		}
+	}
+}
+
+func TestLinksEqual(t *testing.T) {
+	t.Parallel()
+	a := webLinks.Parse(`</a>; rel=next, </b>; rel=prev, </a>; rel=next`)
+	if !a.Equal(webLinks.Parse(`</b>; rel=prev, </a>; rel=next, </a>; rel="next"`)) {
+		t.Fatalf("Expected the order not to matter\n")
+	}
+	if a.Equal(webLinks.Parse(`</b>; rel=prev, </b>; rel=prev, </a>; rel=next`)) {
+		t.Fatalf("Expected each link to be matched once\n")
+	}
+	if a.Equal(webLinks.Parse(`</b>; rel=prev, </a>; rel=next`)) {
+		t.Fatalf("Expected different lengths to differ\n")
+	}
+	if !webLinks.Links(nil).Equal(webLinks.Links{}) {
+		t.Fatalf("Expected no links to equal no links\n")
+	}
+}
+
+func TestLinksClone(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</a>; rel=alternate; hreflang=de; hreflang=en, </b>; rel=next`)
+	clone := links.Clone()
+	clone[0].Params["rel"] = webLinks.Param{Value: "changed"}
+	clone[0].Duplicates["hreflang"][0].Value = "fr"
+	clone[0].Ordered[0].Value = "changed"
+	clone[1].URI = "/changed"
+
+	if links.String() != `</a>; rel=alternate; hreflang=de; hreflang=en, </b>; rel=next` {
+		t.Fatalf("Modifying the clone changed the original: %s\n", links)
+	}
+	if links[0].Ordered[0].Value != "alternate" {
+		t.Fatalf("Modifying the clone changed the original's Ordered\n")
+	}
+	if !webLinks.Parse(`</a>; rel=next`).Clone().Equal(webLinks.Parse(`</a>; rel=next`)) {
+		t.Fatalf("Expected a clone to be equal\n")
+	}
+	if webLinks.Links(nil).Clone() != nil {
+		t.Fatalf("Expected nil\n")
+	}
+	if link := (webLinks.Link{URI: "/a"}).Clone(); link.Params != nil || link.Ordered != nil {
+		t.Fatalf("Got %+v\n", link)
+	}
+}
+
+func TestLinksGroupBy(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</en>; rel=alternate; hreflang=en, </de>; rel=alternate; HrefLang=de, ` +
+		`</multi>; rel=alternate; hreflang=de; hreflang=en; hreflang=de, </none>; rel=alternate`)
+	groups := links.GroupBy("hreflang")
+	expected := map[string][]string{
+		"en": {"/en", "/multi"},
+		"de": {"/de", "/multi"},
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("Got %v\n", groups)
+	}
+	for value, uris := range expected {
+		if len(groups[value]) != len(uris) {
+			t.Fatalf("Got %s for %q expected %v\n", groups[value], value, uris)
+		}
+		for i, uri := range uris {
+			if groups[value][i].URI != uri {
+				t.Fatalf("Got %s for %q expected %v\n", groups[value], value, uris)
+			}
+		}
+	}
+	if groups := links.GroupBy("type"); len(groups) != 0 {
+		t.Fatalf("Got %v\n", groups)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+	before := webLinks.Parse(`</1>; rel=next, </3>; rel=last; title="Page 3", </a>; rel=alternate, </a>; rel=alternate, <https://example.com/about>; rel=about`)
+	after := webLinks.Parse(`<HTTPS://example.com:443/about>; rel=ABOUT, </2>; rel=next, </3>; rel=last; title="Page three", </a>; rel=alternate`)
+	d := webLinks.Diff(before, after)
+	if len(d.Added) != 1 || d.Added[0].URI != "/2" {
+		t.Fatalf("Added mismatch, got %v\n", d.Added)
+	}
+	if len(d.Removed) != 2 || d.Removed[0].URI != "/1" || d.Removed[1].URI != "/a" {
+		t.Fatalf("Removed mismatch, got %v\n", d.Removed)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].Old.Title() != "Page 3" || d.Changed[0].New.Title() != "Page three" {
+		t.Fatalf("Changed mismatch, got %+v\n", d.Changed)
+	}
+	if d.Empty() {
+		t.Fatalf("Expected differences\n")
+	}
+	if d := webLinks.Diff(after, after.Clone()); !d.Empty() {
+		t.Fatalf("Expected no differences, got %+v\n", d)
+	}
+}
+
+var matchTests = []struct {
+	pattern webLinks.Link
+	match   bool
+}{
+	{webLinks.BuildLink("").Rel("next").Link(), true},
+	{webLinks.BuildLink("").Rel("NEXT").Link(), true},
+	{webLinks.BuildLink("").Rel("next", "last").Link(), false},
+	{webLinks.BuildLink("https://EXAMPLE.com:443/items?page=2").Link(), true},
+	{webLinks.BuildLink("/items?page=2").Rel("next").Link(), false},
+	{webLinks.BuildLink("").Rel("next").Param("per_page", "50").Link(), true},
+	{webLinks.BuildLink("").Rel("next").Param("per_page", "100").Link(), false},
+	{webLinks.BuildLink("").Param("hreflang", "de").Link(), true},
+	{webLinks.BuildLink("").Flag("title").Link(), true},
+	{webLinks.BuildLink("").Flag("media").Link(), false},
+	{webLinks.BuildLink("").Link(), true},
+}
+
+func TestLinkMatches(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`<https://example.com/items?page=2>; rel="next alternate"; per_page=50; hreflang=en; hreflang=de; title="Page 2"`)[0]
+	for _, test := range matchTests {
+		if link.Matches(test.pattern) != test.match {
+			t.Fatalf("Expected %t for %v\n", test.match, test.pattern)
+		}
+	}
+}
+
+func TestLinksMatchesSubset(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</a>; rel=alternate; hreflang=en, </b>; rel=alternate; hreflang=de, </c>; rel=next`)
+	alternate := webLinks.BuildLink("").Rel("alternate").Link()
+	if !links.ContainsMatch(alternate) || links.ContainsMatch(webLinks.BuildLink("").Rel("prev").Link()) {
+		t.Fatalf("ContainsMatch mismatch\n")
+	}
+	if !links.MatchesSubset(webLinks.Links{alternate, alternate, webLinks.BuildLink("/c").Link()}) {
+		t.Fatalf("Expected the patterns to be matched\n")
+	}
+	if links.MatchesSubset(webLinks.Links{alternate, alternate, alternate}) {
+		t.Fatalf("Expected each pattern to need a link of its own\n")
+	}
+	if !links.MatchesSubset(nil) {
+		t.Fatalf("Expected no patterns to be matched\n")
+	}
+	// The first pattern matches both links, but only one matches the second
+	links = webLinks.Parse(`</a>; rel=alternate; hreflang=de, </b>; rel=alternate`)
+	german := webLinks.BuildLink("").Rel("alternate").Hreflang("de").Link()
+	if !links.MatchesSubset(webLinks.Links{alternate, german}) {
+		t.Fatalf("Expected the generic pattern to leave /a to the other\n")
+	}
+}
@@ -0,0 +1,216 @@
+package webLinks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// A LinkSet is a set of links along with their context: the resource they
+// were found on, usually the URL that was requested. Links only make sense
+// relative to that, as RFC 8288, section 2 defines a link as a (context,
+// relation type, target) triple, and a LinkSet can tell each link's in full,
+// applying any anchor.
+type LinkSet struct {
+	Context *url.URL
+	Links   Links
+}
+
+// NewLinkSet returns the set of links found on the resource at context.
+func NewLinkSet(context *url.URL, links Links) LinkSet {
+	return LinkSet{Context: context, Links: links}
+}
+
+// LinkSetFromResponse returns the links of resp, as FromResponse does, in a
+// set whose context is resp's base URI, as ResponseBase finds it.
+func LinkSetFromResponse(resp *http.Response) (LinkSet, error) {
+	links, err := FromResponse(resp)
+	return NewLinkSet(ResponseBase(resp), links), err
+}
+
+// A Triple is a link as RFC 8288, section 2 defines it: a statement that the
+// resource at Context has a relation of type Rel to the one at Target.
+type Triple struct {
+	Context *url.URL
+	Rel     string
+	Target  *url.URL
+	Link    Link // the link the triple is from, for its target attributes
+}
+
+func (t Triple) String() string {
+	return fmt.Sprintf("<%s> %s <%s>", t.Context, t.Rel, t.Target)
+}
+
+// ContextOf returns the context of link, its anchor resolved against the
+// set's context, or the set's context itself if it has none. See
+// Link.Context.
+func (s LinkSet) ContextOf(link Link) (*url.URL, error) {
+	return link.Context(s.Context)
+}
+
+// TargetOf returns the target of link, resolved against the set's context.
+func (s LinkSet) TargetOf(link Link) (*url.URL, error) {
+	target, err := link.URL()
+	if err != nil || s.Context == nil {
+		return target, err
+	}
+	return s.Context.ResolveReference(target), nil
+}
+
+// Triples returns a triple for each relation type of each link in the set, in
+// order. Registered relation types are lowercased, as they are compared
+// case-insensitively. Links without a rel are left out, and so are those
+// whose target or anchor can't be parsed, which are reported by the returned
+// error.
+func (s LinkSet) Triples() ([]Triple, error) {
+	var triples []Triple
+	var errs []error
+	for _, link := range s.Links {
+		context, err := s.ContextOf(link)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		target, err := s.TargetOf(link)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, rel := range link.relTypes() {
+			triples = append(triples, Triple{Context: context, Rel: normalRel(rel), Target: target, Link: link})
+		}
+	}
+	return triples, errors.Join(errs...)
+}
+
+// About returns the links in the set whose context is the resource at u, in
+// order: those without an anchor if u is the set's context, and those whose
+// anchor resolves to u. URIs are compared once normalized, as Dedupe does.
+func (s LinkSet) About(u *url.URL) Links {
+	want := normalURI(u.String())
+	return s.Links.Filter(func(link Link) bool {
+		context, err := s.ContextOf(link)
+		return err == nil && context != nil && normalURI(context.String()) == want
+	})
+}
+
+// maxLinkSetBody is how much of a linkset FetchLinkSets reads.
+const maxLinkSetBody = 4 << 20
+
+// FetchLinkSets fetches the linksets of resp's rel=linkset links, as
+// described in RFC 9264, section 6, for APIs that keep sets of links too
+// large for a header in a resource of their own. It returns the links of
+// resp, from LinkSetFromResponse, followed by those of the linksets, with
+// the same links unified as Merge does, links being the same if they have
+// the same target, relation types and context, however their anchors state
+// it. The linksets are fetched with GET
+// requests made with ctx, using client, or http.DefaultClient if client is
+// nil.
+//
+// Links in a linkset that have no anchor are about the linkset itself, and
+// are given it as their anchor, so that they aren't taken to be about resp.
+// Linksets that can't be fetched, or aren't in a format that ParseLinkSet
+// knows, are reported by the returned error, along with the links that
+// could be had.
+func FetchLinkSets(ctx context.Context, client *http.Client, resp *http.Response) (LinkSet, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	set, err := LinkSetFromResponse(resp)
+	errs := []error{err}
+	key := func(link Link) string {
+		key := normalURI(link.URI) + " " + link.relSet()
+		if context, err := set.ContextOf(link); err == nil && context != nil {
+			key += " " + normalURI(context.String())
+		}
+		return key
+	}
+	for _, link := range set.Links.Rel("linkset") {
+		target, err := set.TargetOf(link)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fetched, err := fetchLinkSet(ctx, client, target)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		set.Links = Merge(set.Links, fetched, MergeKey(key))
+	}
+	for i := range set.Links {
+		set.Links[i].Index = i
+	}
+	return set, errors.Join(errs...)
+}
+
+// fetchLinkSet fetches and parses the linkset at u.
+func fetchLinkSet(ctx context.Context, client *http.Client, u *url.URL) (Links, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/linkset+json, application/linkset;q=0.9")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("webLinks: fetching linkset %s: %s", u, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxLinkSetBody))
+	if err != nil {
+		return nil, err
+	}
+	return ParseLinkSet(resp.Header.Get("Content-Type"), body, ResponseBase(resp))
+}
+
+// ParseLinkSet parses body, a linkset whose media type is contentType, as
+// RFC 9264 describes, returning its links with their targets and anchors
+// resolved against base, the URL the linkset was fetched from. Links without
+// an anchor are given base as theirs, being about the linkset itself. The
+// application/linkset format is that of a Link header whose links may be
+// separated by newlines as well, and application/linkset+json is parsed as
+// LinkSet.UnmarshalJSON does.
+func ParseLinkSet(contentType string, body []byte, base *url.URL) (Links, error) {
+	mediatype, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("webLinks: linkset media type: %w", err)
+	}
+	var links Links
+	switch mediatype {
+	case "application/linkset":
+		links, err = ParseStrict(unfold(body))
+	case "application/linkset+json":
+		var set LinkSet
+		err = json.Unmarshal(body, &set)
+		links = set.Links
+	default:
+		return nil, fmt.Errorf("webLinks: linkset in unsupported format %s", mediatype)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		return links, nil
+	}
+	for i, link := range links {
+		if _, ok := link.Anchor(); !ok {
+			links[i] = link.withParam("anchor", base.String())
+		}
+	}
+	return links.resolve(base)
+}
+
+// unfold returns body, links in the format of a Link header that may also be
+// separated by newlines, with the newlines made spaces.
+func unfold(body []byte) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(string(body))
+}
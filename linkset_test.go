@@ -0,0 +1,137 @@
+package webLinks_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestLinkSetTriples(t *testing.T) {
+	t.Parallel()
+	context, _ := url.Parse("https://example.com/articles/1")
+	set := webLinks.NewLinkSet(context, webLinks.Parse(`<2>; rel="Next last", </authors/jo>; rel=author; anchor="#comment-3", <http://other.example/>; rel=related; anchor="https://example.com/", </x>`))
+	triples, err := set.Triples()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	expected := []string{
+		"<https://example.com/articles/1> next <https://example.com/articles/2>",
+		"<https://example.com/articles/1> last <https://example.com/articles/2>",
+		"<https://example.com/articles/1#comment-3> author <https://example.com/authors/jo>",
+		"<https://example.com/> related <http://other.example/>",
+	}
+	if len(triples) != len(expected) {
+		t.Fatalf("Length mismatch, got %v expected %v\n", triples, expected)
+	}
+	for i, triple := range triples {
+		if triple.String() != expected[i] {
+			t.Fatalf("Mismatch at %d, got %s expected %s\n", i, triple, expected[i])
+		}
+	}
+	if triples[2].Link.URI != "/authors/jo" {
+		t.Fatalf("Expected the triple to keep its link, got %+v\n", triples[2].Link)
+	}
+}
+
+func TestLinkSetTriplesErrors(t *testing.T) {
+	t.Parallel()
+	set := webLinks.NewLinkSet(nil, webLinks.Links{
+		{URI: "%zz", Params: map[string]webLinks.Param{"rel": {Value: "next"}}},
+		{URI: "/a", Params: map[string]webLinks.Param{"rel": {Value: "prev"}, "anchor": {Value: "%zz"}}},
+		{URI: "/b", Params: map[string]webLinks.Param{"rel": {Value: "up"}}},
+	})
+	triples, err := set.Triples()
+	if err == nil || len(triples) != 1 || triples[0].Rel != "up" || triples[0].Context != nil || triples[0].Target.String() != "/b" {
+		t.Fatalf("Expected only the valid link, unresolved, got %v %v\n", triples, err)
+	}
+}
+
+func TestLinkSetAbout(t *testing.T) {
+	t.Parallel()
+	context, _ := url.Parse("https://example.com/a")
+	set := webLinks.NewLinkSet(context, webLinks.Parse(`</1>; rel=next, </2>; rel=next; anchor="/b", </3>; rel=up; anchor="HTTPS://example.com:443/b", </4>; rel=up; anchor="/a"`))
+	b, _ := url.Parse("https://example.com/b")
+	if about := set.About(b); len(about) != 2 || about[0].URI != "/2" || about[1].URI != "/3" {
+		t.Fatalf("Got %v\n", about)
+	}
+	if about := set.About(context); len(about) != 2 || about[0].URI != "/1" || about[1].URI != "/4" {
+		t.Fatalf("Got %v\n", about)
+	}
+}
+
+func TestLinkSetFromResponse(t *testing.T) {
+	t.Parallel()
+	req, _ := http.NewRequest("GET", "https://example.com/a/b", nil)
+	resp := &http.Response{
+		Request: req,
+		Header:  http.Header{"Link": {`<c>; rel=next`}},
+	}
+	set, err := webLinks.LinkSetFromResponse(resp)
+	if err != nil || set.Context.String() != "https://example.com/a/b" || set.Links[0].URI != "https://example.com/a/c" {
+		t.Fatalf("Got %+v %v\n", set, err)
+	}
+}
+
+func TestParseLinkSet(t *testing.T) {
+	t.Parallel()
+	base, _ := url.Parse("https://example.com/linksets/1")
+	body := "<https://example.com/articles/1>; rel=author; anchor=\"/articles/1\",\r\n" +
+		"   <../widgets>; rel=related,\n  <https://example.com/articles/2>;\n rel=next; anchor=\"https://example.com/articles/1\"\n"
+	links, err := webLinks.ParseLinkSet("application/linkset; charset=utf-8", []byte(body), base)
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	if len(links) != 3 {
+		t.Fatalf("Expected 3 links, got %v\n", links)
+	}
+	if anchor, _ := links[0].Anchor(); anchor != "https://example.com/articles/1" {
+		t.Fatalf("Got anchor %q\n", anchor)
+	}
+	if anchor, _ := links[1].Anchor(); anchor != base.String() || links[1].URI != "https://example.com/widgets" {
+		t.Fatalf("Expected a link without an anchor to be about the linkset, got %v\n", links[1])
+	}
+	if _, err := webLinks.ParseLinkSet("text/plain", []byte(body), base); err == nil {
+		t.Fatalf("Expected an error for an unknown format\n")
+	}
+	if _, err := webLinks.ParseLinkSet("application/linkset", []byte("<a>; rel=next, <b"), base); err == nil {
+		t.Fatalf("Expected an error for a malformed linkset\n")
+	}
+}
+
+func TestFetchLinkSets(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/linkset":
+			w.Header().Set("Content-Type", "application/linkset")
+			fmt.Fprint(w, "</items?page=2>; rel=next; anchor=\"/items\",\n</items/schema>; rel=describedby; anchor=\"/items\"")
+		case "/broken":
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	resp := &http.Response{
+		Header: http.Header{"Link": {`</linkset>; rel=linkset; type="application/linkset", </items?page=2>; rel=next, </broken>; rel=linkset`}},
+	}
+	resp.Request, _ = http.NewRequest("GET", srv.URL+"/items", nil)
+	set, err := webLinks.FetchLinkSets(context.Background(), srv.Client(), resp)
+	if err == nil {
+		t.Fatalf("Expected an error for the broken linkset\n")
+	}
+	if set.Context.String() != srv.URL+"/items" {
+		t.Fatalf("Got context %v\n", set.Context)
+	}
+	// The linkset's next link is the same as the response's
+	if len(set.Links) != 4 || set.Links[3].URI != srv.URL+"/items/schema" || set.Links[3].Index != 3 {
+		t.Fatalf("Got %v\n", set.Links)
+	}
+	about := set.About(set.Context)
+	if len(about) != 4 || !about.ContainsMatch(webLinks.BuildLink("").Rel("describedby").Link()) {
+		t.Fatalf("Expected the linkset's links to be about the resource, got %v\n", about)
+	}
+}
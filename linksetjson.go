@@ -0,0 +1,325 @@
+package webLinks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MarshalJSON implements json.Marshaler, representing the set in the
+// application/linkset+json format of RFC 9264, section 4.2: an object whose
+// "linkset" holds an object for each context, in the order they first
+// appear, its "anchor" followed by the targets of each relation type, like
+// so:
+//
+//	{"linkset": [{
+//		"anchor": "https://example.com/items",
+//		"next": [{"href": "https://example.com/items?page=2"}],
+//		"alternate": [{
+//			"href": "https://example.com/items.fr",
+//			"hreflang": ["fr"],
+//			"title*": [{"value": "Éléments", "language": "fr"}]
+//		}]
+//	}]}
+//
+// Anchors and targets are resolved against the set's context. hreflang and
+// extension attributes are arrays of every value they have, while title,
+// type and media are strings. Values that must be written as ext-values in a
+// header are written as internationalized ones instead, with a "*" appended
+// to their name, as objects holding their "value" and "language". A link is
+// listed under each of its relation types. Links without a rel, and params
+// without a value, have no place in a linkset and are left out.
+func (s LinkSet) MarshalJSON() ([]byte, error) {
+	type context struct {
+		anchor string
+		rels   []string
+		by     map[string][][]byte
+	}
+	var contexts []*context
+	index := map[string]*context{}
+	for _, link := range s.Links {
+		anchor, err := s.ContextOf(link)
+		if err != nil {
+			return nil, err
+		}
+		target, err := s.TargetOf(link)
+		if err != nil {
+			return nil, err
+		}
+		key := ""
+		if anchor != nil {
+			key = anchor.String()
+		}
+		c, ok := index[key]
+		if !ok {
+			c = &context{anchor: key, by: map[string][][]byte{}}
+			index[key] = c
+			contexts = append(contexts, c)
+		}
+		object := link.targetObject(target.String())
+		for _, rel := range link.relTypes() {
+			rel = normalRel(rel)
+			if _, ok := c.by[rel]; !ok {
+				c.rels = append(c.rels, rel)
+			}
+			c.by[rel] = append(c.by[rel], object)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"linkset":[`)
+	for i, c := range contexts {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		if c.anchor != "" {
+			buf.WriteString(`"anchor":`)
+			writeJSONString(&buf, c.anchor)
+		}
+		for j, rel := range c.rels {
+			if j > 0 || c.anchor != "" {
+				buf.WriteByte(',')
+			}
+			writeJSONString(&buf, rel)
+			buf.WriteString(":[")
+			buf.Write(bytes.Join(c.by[rel], []byte(",")))
+			buf.WriteByte(']')
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteString("]}")
+	return buf.Bytes(), nil
+}
+
+// targetObject returns the link's target object, as MarshalJSON writes it,
+// with href as its href.
+func (l Link) targetObject(href string) []byte {
+	members := map[string][]interface{}{}
+	for name := range l.Params {
+		lower := strings.ToLower(name)
+		if lower == "rel" || lower == "anchor" {
+			continue
+		}
+		for _, p := range l.values(name) {
+			switch {
+			case p.Flag:
+			case p.extended():
+				value := map[string]string{"value": p.Value}
+				if p.Lang != "" {
+					value["language"] = p.Lang
+				}
+				members[lower+"*"] = append(members[lower+"*"], value)
+			default:
+				members[lower] = append(members[lower], p.Value)
+			}
+		}
+	}
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"href":`)
+	writeJSONString(&buf, href)
+	for _, name := range names {
+		buf.WriteByte(',')
+		writeJSONString(&buf, name)
+		buf.WriteByte(':')
+		values := members[name]
+		var v interface{} = values
+		if name == "title" || name == "type" || name == "media" {
+			// Only the first counts
+			v = values[0]
+		}
+		// Strings and maps of them always marshal
+		b, _ := json.Marshal(v)
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// writeJSONString writes s to buf as a JSON string.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	// Strings always marshal
+	b, _ := json.Marshal(s)
+	buf.Write(b)
+}
+
+// internationalized is an internationalized target attribute value, as
+// RFC 9264, section 4.2.4.2 describes.
+type internationalized struct {
+	Value    string `json:"value"`
+	Language string `json:"language"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler, parsing a linkset in the
+// application/linkset+json format, as MarshalJSON writes it, into the set's
+// links. Each target object becomes a link of the relation type it is listed
+// under, anchored at its context's anchor, if it has one, in the order they
+// appear. The links are left as they are, relative or not; see ParseLinkSet
+// for resolving them. Context is left alone.
+//
+// Internationalized values become UTF-8 ext-values in the language they
+// state. An error wrapping ErrBadValue is returned for a linkset that
+// doesn't have the shape RFC 9264 describes, one wrapping ErrBadRel for
+// members that aren't named by a relation type, and the errors Links.Build
+// returns for links that wouldn't make a valid Link header.
+func (s *LinkSet) UnmarshalJSON(data []byte) error {
+	var doc struct {
+		Linkset []json.RawMessage `json:"linkset"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%w: linkset: %v", ErrBadValue, err)
+	}
+	if doc.Linkset == nil {
+		return fmt.Errorf("%w: linkset has no linkset member", ErrBadValue)
+	}
+	var links Links
+	for _, raw := range doc.Linkset {
+		found, err := decodeContext(raw)
+		if err != nil {
+			return err
+		}
+		for _, link := range found {
+			link.Index = len(links)
+			links = append(links, link)
+		}
+	}
+	s.Links = links
+	return nil
+}
+
+// decodeContext decodes a context object of a linkset into its links, in
+// the order they appear.
+func decodeContext(raw json.RawMessage) (Links, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("%w: linkset context is not an object", ErrBadValue)
+	}
+	type relTargets struct {
+		rel     string
+		targets []map[string]json.RawMessage
+	}
+	var anchor *string
+	var rels []relTargets
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: linkset: %v", ErrBadValue, err)
+		}
+		name := tok.(string)
+		if name == "anchor" {
+			var a string
+			if err := dec.Decode(&a); err != nil {
+				return nil, fmt.Errorf("%w: linkset anchor: %v", ErrBadValue, err)
+			}
+			anchor = &a
+			continue
+		}
+		if !isRelType(name) {
+			return nil, fmt.Errorf("%w: %q is not a relation type", ErrBadRel, name)
+		}
+		var targets []map[string]json.RawMessage
+		if err := dec.Decode(&targets); err != nil {
+			return nil, fmt.Errorf("%w: linkset targets of %q: %v", ErrBadValue, name, err)
+		}
+		rels = append(rels, relTargets{name, targets})
+	}
+
+	var links Links
+	for _, r := range rels {
+		for _, target := range r.targets {
+			link, err := decodeTarget(r.rel, anchor, target)
+			if err != nil {
+				return nil, err
+			}
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// decodeTarget decodes a target object of a linkset, listed under rel, into
+// its link.
+func decodeTarget(rel string, anchor *string, target map[string]json.RawMessage) (Link, error) {
+	var href string
+	if err := json.Unmarshal(target["href"], &href); err != nil || target["href"] == nil {
+		return Link{}, fmt.Errorf("%w: linkset target of %q has no href", ErrBadValue, rel)
+	}
+	l := Link{URI: escapeURI(href), Params: map[string]Param{}}
+	l.addValue("rel", plainParam(rel))
+	if anchor != nil {
+		l.addValue("anchor", plainParam(*anchor))
+	}
+	names := make([]string, 0, len(target))
+	for name := range target {
+		if name != "href" {
+			names = append(names, name)
+		}
+	}
+	// So that title comes before title*
+	sort.Strings(names)
+	for _, name := range names {
+		raw := target[name]
+		switch {
+		case strings.HasSuffix(name, "*"):
+			var values []internationalized
+			if err := json.Unmarshal(raw, &values); err != nil {
+				return Link{}, fmt.Errorf("%w: linkset attribute %q: %v", ErrBadValue, name, err)
+			}
+			for _, v := range values {
+				l.addValue(strings.TrimSuffix(name, "*"), Param{Value: v.Value, Enc: "UTF-8", Lang: v.Language})
+			}
+		case name == "title" || name == "type" || name == "media":
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return Link{}, fmt.Errorf("%w: linkset attribute %q: %v", ErrBadValue, name, err)
+			}
+			l.addValue(name, plainParam(value))
+		default:
+			var values []string
+			if err := json.Unmarshal(raw, &values); err != nil {
+				return Link{}, fmt.Errorf("%w: linkset attribute %q: %v", ErrBadValue, name, err)
+			}
+			for _, v := range values {
+				l.addValue(name, plainParam(v))
+			}
+		}
+	}
+	if err := l.validate(); err != nil {
+		return Link{}, err
+	}
+	return l, nil
+}
+
+// plainParam returns a param holding value, in US-ASCII, or UTF-8 if it
+// holds anything else, as LinkBuilder.Param makes them.
+func plainParam(value string) Param {
+	if !isASCII(value) {
+		return Param{Value: value, Enc: "UTF-8"}
+	}
+	return Param{Value: value, Enc: "us-ascii", Lang: "en-us"}
+}
+
+// addValue adds a value of the param called name to the link, keeping any
+// it has already in Duplicates, the first in Params.
+func (l *Link) addValue(name string, param Param) {
+	prev, ok := l.Params[name]
+	if !ok {
+		l.Params[name] = param
+		return
+	}
+	if l.Duplicates == nil {
+		l.Duplicates = map[string][]Param{}
+	}
+	if _, ok := l.Duplicates[name]; !ok {
+		l.Duplicates[name] = []Param{prev}
+	}
+	l.Duplicates[name] = append(l.Duplicates[name], param)
+}
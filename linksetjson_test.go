@@ -0,0 +1,120 @@
+package webLinks_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestLinkSetMarshalJSON(t *testing.T) {
+	t.Parallel()
+	context, _ := url.Parse("https://example.com/items")
+	set := webLinks.NewLinkSet(context, webLinks.Parse(`<?page=2>; rel="next Last", `+
+		`</items.fr>; rel=alternate; hreflang=fr; hreflang=fr-CA; title*=UTF-8'fr'%C3%89l%C3%A9ments; type=text/html; foo=bar; flag, `+
+		`</about>; rel=author; anchor="/"`))
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	want := `{"linkset":[{"anchor":"https://example.com/items",` +
+		`"next":[{"href":"https://example.com/items?page=2"}],"last":[{"href":"https://example.com/items?page=2"}],` +
+		`"alternate":[{"href":"https://example.com/items.fr","foo":["bar"],"hreflang":["fr","fr-CA"],` +
+		`"title*":[{"language":"fr","value":"Éléments"}],"type":"text/html"}]},` +
+		`{"anchor":"https://example.com/","author":[{"href":"https://example.com/about"}]}]}`
+	if string(b) != want {
+		t.Fatalf("Got %s\nexpected %s\n", b, want)
+	}
+}
+
+func TestLinkSetUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+	data := `{"linkset": [
+		{"anchor": "https://example.net/bar",
+		 "next": [{"href": "https://example.com/foo1", "type": "text/html"}, {"href": "/foo2"}],
+		 "alternate": [{"href": "https://example.com/fr", "hreflang": ["fr", "fr-CA"], "title": "Items",
+		                "title*": [{"value": "Éléments", "language": "fr"}], "ext": ["a", "b"]}]},
+		{"http://example.com/rel/custom": [{"href": "/custom"}]}
+	]}`
+	var set webLinks.LinkSet
+	if err := json.Unmarshal([]byte(data), &set); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	links := set.Links
+	if len(links) != 4 {
+		t.Fatalf("Expected 4 links, got %v\n", links)
+	}
+	if !links[0].HasRel("next") || links[0].Type() != "text/html" || links[1].URI != "/foo2" || links[3].Index != 3 {
+		t.Fatalf("Got %v\n", links)
+	}
+	if anchor, ok := links[1].Anchor(); !ok || anchor != "https://example.net/bar" {
+		t.Fatalf("Got anchor %q\n", anchor)
+	}
+	alt := links[2]
+	if alt.Title() != "Éléments" || len(alt.Hreflang()) != 2 || len(alt.Values("ext")) != 2 {
+		t.Fatalf("Got %v\n", alt)
+	}
+	if title := alt.Attributes(); title.Title != "Items" || title.TitleStar != "Éléments" {
+		t.Fatalf("Got title %q and title* %q\n", title.Title, title.TitleStar)
+	}
+	if _, ok := links[3].Anchor(); ok || !links[3].HasRel("http://example.com/rel/custom") {
+		t.Fatalf("Got %v\n", links[3])
+	}
+}
+
+func TestLinkSetJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+	context, _ := url.Parse("https://example.com/items")
+	set := webLinks.NewLinkSet(context, webLinks.Parse(`<https://example.com/items?page=2>; rel=next; anchor="https://example.com/items", `+
+		`<https://example.com/fr>; rel=alternate; anchor="https://example.com/items"; hreflang=fr; title*=UTF-8'fr'%C3%89l%C3%A9ments`))
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	var back webLinks.LinkSet
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	if back.Links.Canonical() != set.Links.Canonical() {
+		t.Fatalf("Got %s\nexpected %s\n", back.Links.Canonical(), set.Links.Canonical())
+	}
+}
+
+var badLinkSetJSONTests = []string{
+	`[]`,
+	`{}`,
+	`{"linkset": [[]]}`,
+	`{"linkset": [{"next": {"href": "/a"}}]}`,
+	`{"linkset": [{"next": [{"type": "text/html"}]}]}`,
+	`{"linkset": [{"next": [{"href": "/a", "hreflang": "en"}]}]}`,
+	`{"linkset": [{"next": [{"href": "/a", "title*": ["x"]}]}]}`,
+	`{"linkset": [{"bad rel": [{"href": "/a"}]}]}`,
+}
+
+func TestLinkSetUnmarshalJSONErrors(t *testing.T) {
+	t.Parallel()
+	for _, data := range badLinkSetJSONTests {
+		var set webLinks.LinkSet
+		if err := json.Unmarshal([]byte(data), &set); err == nil {
+			t.Fatalf("Expected an error for %s\n", data)
+		}
+	}
+	var set webLinks.LinkSet
+	if err := json.Unmarshal([]byte(`{"linkset": [{"next": [{"href": 1}]}]}`), &set); !errors.Is(err, webLinks.ErrBadValue) {
+		t.Fatalf("Expected ErrBadValue, got %v\n", err)
+	}
+}
+
+func TestParseLinkSetJSON(t *testing.T) {
+	t.Parallel()
+	base, _ := url.Parse("https://example.com/linksets/1")
+	links, err := webLinks.ParseLinkSet("application/linkset+json", []byte(`{"linkset": [{"next": [{"href": "../items?page=2"}]}]}`), base)
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	if anchor, _ := links[0].Anchor(); len(links) != 1 || links[0].URI != "https://example.com/items?page=2" || anchor != base.String() {
+		t.Fatalf("Got %v\n", links)
+	}
+}
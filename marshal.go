@@ -0,0 +1,290 @@
+package webLinks
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MarshalText implements encoding.TextMarshaler, formatting the link as a
+// Link header field value, as String does.
+func (l Link) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing text strictly as
+// a Link header field value holding exactly one link.
+func (l *Link) UnmarshalText(text []byte) error {
+	links, err := ParseStrict(string(text))
+	if err != nil {
+		return err
+	}
+	if len(links) != 1 {
+		return fmt.Errorf("webLinks: expected one link, got %d", len(links))
+	}
+	*l = links[0]
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, formatting the links as a
+// Link header field value, as String does.
+func (l Links) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing text strictly as
+// a Link header field value. Empty text stands for no links at all.
+func (l *Links) UnmarshalText(text []byte) error {
+	links, err := ParseStrict(string(text))
+	if err != nil && !(len(text) == 0 && errors.Is(err, ErrNoLinks)) {
+		return err
+	}
+	*l = links
+	return nil
+}
+
+// jsonParam is how a Param is represented in JSON.
+type jsonParam struct {
+	Value string `json:"value"`
+	Enc   string `json:"enc,omitempty"`
+	Lang  string `json:"lang,omitempty"`
+	Flag  bool   `json:"flag,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, representing the param as an
+// object with its "value", "enc" and "lang", and "flag": true for params
+// that had no value at all. Raw is left out.
+func (p Param) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonParam{Value: p.Value, Enc: p.Enc, Lang: p.Lang, Flag: p.Flag})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, the inverse of MarshalJSON.
+func (p *Param) UnmarshalJSON(data []byte) error {
+	var param jsonParam
+	if err := json.Unmarshal(data, &param); err != nil {
+		return err
+	}
+	*p = Param{Value: param.Value, Enc: param.Enc, Lang: param.Lang, Flag: param.Flag}
+	return nil
+}
+
+// jsonLink is how a Link is represented in JSON.
+type jsonLink struct {
+	URI        string             `json:"uri"`
+	Params     map[string]Param   `json:"params"`
+	Duplicates map[string][]Param `json:"duplicates,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, representing the link as an object
+// with its "uri", its "params" by name, and the "duplicates" of any that
+// appeared more than once, like so:
+//
+//	{
+//		"uri": "/page/2",
+//		"params": {
+//			"rel": {"value": "next", "enc": "us-ascii", "lang": "en-us"},
+//			"hreflang": {"value": "en", "enc": "us-ascii", "lang": "en-us"}
+//		},
+//		"duplicates": {
+//			"hreflang": [
+//				{"value": "de", "enc": "us-ascii", "lang": "en-us"},
+//				{"value": "en", "enc": "us-ascii", "lang": "en-us"}
+//			]
+//		}
+//	}
+//
+// Ordered is left out, so the params of a link read back are formatted in
+// the order String uses, but are otherwise the same.
+func (l Link) MarshalJSON() ([]byte, error) {
+	params := l.Params
+	if params == nil {
+		params = map[string]Param{}
+	}
+	return json.Marshal(jsonLink{URI: l.URI, Params: params, Duplicates: l.Duplicates})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, the inverse of MarshalJSON.
+func (l *Link) UnmarshalJSON(data []byte) error {
+	var link jsonLink
+	if err := json.Unmarshal(data, &link); err != nil {
+		return err
+	}
+	if link.Params == nil {
+		link.Params = map[string]Param{}
+	}
+	*l = Link{URI: link.URI, Params: link.Params, Duplicates: link.Duplicates}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, representing the links as an array
+// of links, each as Link.MarshalJSON does. No links make an empty array.
+func (l Links) MarshalJSON() ([]byte, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]Link(l))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, the inverse of MarshalJSON.
+func (l *Links) UnmarshalJSON(data []byte) error {
+	var links []Link
+	if err := json.Unmarshal(data, &links); err != nil {
+		return err
+	}
+	*l = links
+	return nil
+}
+
+var (
+	urlType             = reflect.TypeOf(url.URL{})
+	timeType            = reflect.TypeOf(time.Time{})
+	paramType           = reflect.TypeOf(Param{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// UnmarshalParams stores the link's params in the fields of the struct v
+// points to, by the names given in their "weblink" tags, matched ignoring
+// case as Param does, so that "title" finds a title* too, like so:
+//
+//	var attrs struct {
+//		Title    string    `weblink:"title"`
+//		Hreflang []string  `weblink:"hreflang"`
+//		Count    int       `weblink:"count"`
+//		Updated  time.Time `weblink:"updated"`
+//	}
+//	err := link.UnmarshalParams(&attrs)
+//
+// Fields without a tag, or tagged "-", are left alone, and so are those for
+// params the link doesn't have. Values are converted to the type of the field:
+//
+//   - strings get the value as it is, and Params the whole param
+//   - ints and uints get the value parsed as a decimal number
+//   - bools are set if the param is present at all, as flags are
+//   - url.URLs get the value parsed as a URI-reference
+//   - time.Times get the value parsed as an HTTP-date, or as RFC 3339
+//   - types implementing encoding.TextUnmarshaler unmarshal the value
+//   - pointers to any of these are allocated as needed
+//   - slices get an element for every occurrence of the param, as Values
+//     returns them; for rel and rev, every relation type they hold instead
+//
+// Values that can't be converted leave their field as it was, and are
+// reported together in an error wrapping ErrBadValue, once every other field
+// has been set.
+func (l Link) UnmarshalParams(v interface{}) error {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("webLinks: UnmarshalParams needs a pointer to a struct, got %T", v)
+	}
+	s := ptr.Elem()
+	var errs []error
+	for i := 0; i < s.NumField(); i++ {
+		field := s.Type().Field(i)
+		name := field.Tag.Get("weblink")
+		if name == "" || name == "-" || field.PkgPath != "" {
+			continue
+		}
+		values := l.values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if err := setParam(s.Field(i), name, values); err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", field.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setParam stores the param called name, which appeared with values, in f.
+func setParam(f reflect.Value, name string, values []Param) error {
+	if f.Kind() != reflect.Slice || f.Addr().Type().Implements(textUnmarshalerType) {
+		return setValue(f, name, values[0])
+	}
+	if n := strings.ToLower(name); n == "rel" || n == "rev" {
+		var types []Param
+		for _, t := range strings.Fields(values[0].Value) {
+			types = append(types, Param{Value: t, Enc: values[0].Enc, Lang: values[0].Lang})
+		}
+		values = types
+	}
+	slice := reflect.MakeSlice(f.Type(), len(values), len(values))
+	for i, value := range values {
+		if err := setValue(slice.Index(i), name, value); err != nil {
+			return err
+		}
+	}
+	f.Set(slice)
+	return nil
+}
+
+// setValue stores param, found as the param called name, in f.
+func setValue(f reflect.Value, name string, param Param) error {
+	switch f.Type() {
+	case paramType:
+		f.Set(reflect.ValueOf(param))
+		return nil
+	case urlType:
+		u, err := url.Parse(param.Value)
+		if err != nil {
+			return badParam(name, param, err)
+		}
+		f.Set(reflect.ValueOf(*u))
+		return nil
+	case timeType:
+		t, err := http.ParseTime(param.Value)
+		if err != nil {
+			if t, err = time.Parse(time.RFC3339, param.Value); err != nil {
+				return badParam(name, param, err)
+			}
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if f.Kind() == reflect.Ptr {
+		elem := reflect.New(f.Type().Elem())
+		if err := setValue(elem.Elem(), name, param); err != nil {
+			return err
+		}
+		f.Set(elem)
+		return nil
+	}
+	if f.Addr().Type().Implements(textUnmarshalerType) {
+		if err := f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(param.Value)); err != nil {
+			return badParam(name, param, err)
+		}
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(param.Value)
+	case reflect.Bool:
+		f.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(param.Value, 10, f.Type().Bits())
+		if err != nil {
+			return badParam(name, param, err)
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(param.Value, 10, f.Type().Bits())
+		if err != nil {
+			return badParam(name, param, err)
+		}
+		f.SetUint(n)
+	default:
+		return fmt.Errorf("webLinks: can't unmarshal parameter %q into a %s", name, f.Type())
+	}
+	return nil
+}
+
+// badParam returns the error for a param whose value couldn't be converted.
+func badParam(name string, param Param, err error) error {
+	return fmt.Errorf("%w: parameter %q: %q: %v", ErrBadValue, name, param.Value, err)
+}
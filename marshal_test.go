@@ -0,0 +1,206 @@
+package webLinks_test
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conslo/webLinks"
+)
+
+var (
+	_ encoding.TextMarshaler   = webLinks.Link{}
+	_ encoding.TextUnmarshaler = &webLinks.Link{}
+	_ encoding.TextMarshaler   = webLinks.Links{}
+	_ encoding.TextUnmarshaler = &webLinks.Links{}
+)
+
+func TestLinkTextRoundTrip(t *testing.T) {
+	t.Parallel()
+	const input = `</a>; rel=next; title="Page 2"`
+	var link webLinks.Link
+	if err := link.UnmarshalText([]byte(input)); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	text, err := link.MarshalText()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if string(text) != input {
+		t.Fatalf("Got %s expected %s\n", text, input)
+	}
+}
+
+func TestLinkUnmarshalTextErrors(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{
+		``,
+		`</a>; rel=next, </b>; rel=prev`,
+		`</a>; rel=next; title="Page 2`,
+		`/a; rel=next`,
+	} {
+		link := webLinks.Link{URI: "/unchanged"}
+		if err := link.UnmarshalText([]byte(input)); err == nil {
+			t.Fatalf("Expected an error for %q\n", input)
+		}
+		if link.URI != "/unchanged" {
+			t.Fatalf("Expected the link to be left alone for %q, got %+v\n", input, link)
+		}
+	}
+}
+
+func TestLinksTextFlag(t *testing.T) {
+	t.Parallel()
+	var links webLinks.Links
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.TextVar(&links, "links", webLinks.Links{}, "links to add")
+	if err := flags.Parse([]string{"-links", `</a>; rel=next, </b>; rel=prev`}); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if len(links) != 2 || links[1].URI != "/b" {
+		t.Fatalf("Got %+v\n", links)
+	}
+	if text, _ := links.MarshalText(); string(text) != `</a>; rel=next, </b>; rel=prev` {
+		t.Fatalf("Got %s\n", text)
+	}
+
+	if err := links.UnmarshalText(nil); err != nil || len(links) != 0 {
+		t.Fatalf("Expected no links and no error, got %+v %v\n", links, err)
+	}
+	if err := links.UnmarshalText([]byte(`</a>; rel=next, </b`)); err == nil {
+		t.Fatalf("Expected an error\n")
+	}
+}
+
+func TestLinkJSON(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; rel=next; foo`)[0]
+	data, err := json.Marshal(link)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	const expected = `{"uri":"/a","params":{"foo":{"value":"","flag":true},"rel":{"value":"next","enc":"us-ascii","lang":"en-us"}}}`
+	if string(data) != expected {
+		t.Fatalf("Got %s expected %s\n", data, expected)
+	}
+}
+
+func TestLinksJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, input := range roundTripTests {
+		links := webLinks.Parse(input)
+		data, err := json.Marshal(links)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v\n", input, err)
+		}
+		var again webLinks.Links
+		if err := json.Unmarshal(data, &again); err != nil {
+			t.Fatalf("Unexpected error for %s: %v\n", data, err)
+		}
+		if again.String() != links.String() {
+			t.Fatalf("Got %s expected %s from %s\n", again, links, data)
+		}
+		for i, link := range again {
+			for name, param := range links[i].Params {
+				if link.Params[name].Value != param.Value || link.Params[name].Lang != param.Lang {
+					t.Fatalf("Got %+v expected %+v for %q in %s\n", link.Params[name], param, name, data)
+				}
+			}
+		}
+	}
+}
+
+func TestLinksJSONEmpty(t *testing.T) {
+	t.Parallel()
+	if data, _ := json.Marshal(webLinks.Links(nil)); string(data) != `[]` {
+		t.Fatalf("Got %s expected []\n", data)
+	}
+	if data, _ := json.Marshal(webLinks.Link{URI: "/a"}); string(data) != `{"uri":"/a","params":{}}` {
+		t.Fatalf("Got %s\n", data)
+	}
+	var link webLinks.Link
+	if err := json.Unmarshal([]byte(`{"uri":"/a"}`), &link); err != nil || link.Params == nil {
+		t.Fatalf("Expected empty params, got %+v %v\n", link, err)
+	}
+}
+
+type linkParams struct {
+	Title    string             `weblink:"title"`
+	Media    webLinks.Param     `weblink:"media"`
+	Rel      []string           `weblink:"rel"`
+	Hreflang []string           `weblink:"hreflang"`
+	Count    int                `weblink:"count"`
+	Size     *uint16            `weblink:"size"`
+	Missing  *int               `weblink:"missing"`
+	Feed     bool               `weblink:"feed"`
+	Anchor   *url.URL           `weblink:"anchor"`
+	Updated  time.Time          `weblink:"updated"`
+	Created  time.Time          `weblink:"created"`
+	Addr     net.IP             `weblink:"addr"`
+	Level    flag.ErrorHandling // untagged
+	Skipped  string             `weblink:"-"`
+}
+
+func TestUnmarshalParams(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; rel="next last"; TITLE="Page 2"; media*=UTF-8'de'print; hreflang=en; hreflang=de; count=12; size=512; feed; anchor="/b"; updated="Sun, 06 Nov 1994 08:49:37 GMT"; created="1994-11-06T08:49:37Z"; addr="192.0.2.1"; level=1; skipped=x`)[0]
+	var params linkParams
+	if err := link.UnmarshalParams(&params); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	when := time.Date(1994, 11, 6, 8, 49, 37, 0, time.UTC)
+	switch {
+	case params.Title != "Page 2", params.Media.Value != "print", params.Media.Lang != "de":
+		t.Fatalf("Param mismatch, got %q and %+v\n", params.Title, params.Media)
+	case strings.Join(params.Rel, " ") != "next last", strings.Join(params.Hreflang, " ") != "en de":
+		t.Fatalf("Slice mismatch, got %q and %q\n", params.Rel, params.Hreflang)
+	case params.Count != 12, params.Size == nil || *params.Size != 512, params.Missing != nil:
+		t.Fatalf("Number mismatch, got %+v\n", params)
+	case !params.Feed:
+		t.Fatalf("Expected the flag to be set\n")
+	case params.Anchor == nil || params.Anchor.Path != "/b":
+		t.Fatalf("URL mismatch, got %v\n", params.Anchor)
+	case !params.Updated.Equal(when), !params.Created.Equal(when):
+		t.Fatalf("Time mismatch, got %v and %v\n", params.Updated, params.Created)
+	case params.Addr.String() != "192.0.2.1":
+		t.Fatalf("Expected the TextUnmarshaler to be used, got %v\n", params.Addr)
+	case params.Level != 0, params.Skipped != "":
+		t.Fatalf("Expected untagged fields to be left alone, got %+v\n", params)
+	}
+}
+
+func TestUnmarshalParamsErrors(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; rel=next; title=ok; count=many; size=70000; updated=yesterday`)[0]
+	var params linkParams
+	err := link.UnmarshalParams(&params)
+	if !errors.Is(err, webLinks.ErrBadValue) {
+		t.Fatalf("Expected ErrBadValue, got %v\n", err)
+	}
+	for _, field := range []string{"Count", "Size", "Updated"} {
+		if !strings.Contains(err.Error(), "field "+field) {
+			t.Fatalf("Expected %s to be reported, got %v\n", field, err)
+		}
+	}
+	if params.Title != "ok" || params.Count != 0 || params.Size != nil {
+		t.Fatalf("Expected only the valid fields to be set, got %+v\n", params)
+	}
+
+	for _, v := range []interface{}{nil, params, new(string), (*linkParams)(nil)} {
+		if err := link.UnmarshalParams(v); err == nil {
+			t.Fatalf("Expected an error for %T\n", v)
+		}
+	}
+	var unsupported struct {
+		Title chan int `weblink:"title"`
+	}
+	if err := link.UnmarshalParams(&unsupported); err == nil {
+		t.Fatalf("Expected an error for an unsupported field\n")
+	}
+}
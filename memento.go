@@ -0,0 +1,154 @@
+package webLinks
+
+import (
+	"net/http"
+	"sort"
+	"time"
+)
+
+// A Memento is a prior state of a resource, as archived by a web archive
+// and linked to with rel=memento, as described in RFC 7089.
+type Memento struct {
+	Link     Link
+	Datetime time.Time // when the state was archived, from its datetime
+}
+
+// A TimeMap is a list of the mementos of a resource, linked to with
+// rel=timemap, along with the span of time they cover, from its from and
+// until params, which are zero if not given.
+type TimeMap struct {
+	Link        Link
+	From, Until time.Time
+}
+
+// MementoLinks holds the links of the Memento protocol, as described in
+// RFC 7089, section 2.2: the original resource, its TimeGate, which
+// negotiates which memento to serve by datetime, its TimeMaps and whichever
+// of its mementos are linked to. Targets are "" for the links that are
+// absent.
+type MementoLinks struct {
+	Original string
+	TimeGate string
+	TimeMaps []TimeMap
+	Mementos Mementos // ordered by datetime
+}
+
+// Mementos picks out the links of the Memento protocol. The first original
+// and timegate links are taken, and every timemap and memento link, such as
+// rel="first memento". Mementos whose datetime isn't an HTTP date are left
+// out, and so is a timemap's from or until that isn't.
+//
+// See ParseTimeMap for the links of a TimeMap itself, which holds every
+// memento of a resource.
+func (l Links) Mementos() MementoLinks {
+	var m MementoLinks
+	if link, ok := l.First("original"); ok {
+		m.Original = link.URI
+	}
+	if link, ok := l.First("timegate"); ok {
+		m.TimeGate = link.URI
+	}
+	for _, link := range l.Rel("timemap") {
+		tm := TimeMap{Link: link}
+		tm.From, _ = link.Time("from")
+		tm.Until, _ = link.Time("until")
+		m.TimeMaps = append(m.TimeMaps, tm)
+	}
+	for _, link := range l.Rel("memento") {
+		if datetime, ok := link.Time("datetime"); ok {
+			m.Mementos = append(m.Mementos, Memento{Link: link, Datetime: datetime})
+		}
+	}
+	sort.SliceStable(m.Mementos, func(i, j int) bool {
+		return m.Mementos[i].Datetime.Before(m.Mementos[j].Datetime)
+	})
+	return m
+}
+
+// ParseTimeMap parses body, a TimeMap in the application/link-format of
+// RFC 6690, as RFC 7089, section 5 describes them: links as a Link header
+// holds them, though they may be separated by newlines too.
+func ParseTimeMap(body []byte) (Links, error) {
+	return ParseStrict(unfold(body))
+}
+
+// Time parses the link's param called name as an HTTP date, as Memento's
+// datetime, from and until params hold, like
+// datetime="Tue, 20 Jun 2000 03:18:56 GMT". ok is false if the link has
+// no such param, or it isn't an HTTP date.
+func (l Link) Time(name string) (t time.Time, ok bool) {
+	param, ok := l.Param(name)
+	if !ok {
+		return time.Time{}, false
+	}
+	t, err := http.ParseTime(param.Value)
+	return t, err == nil
+}
+
+// MementoDatetime returns the time at which the memento resp is of was
+// archived, from its Memento-Datetime header. ok is false if resp isn't of a
+// memento.
+func MementoDatetime(resp *http.Response) (t time.Time, ok bool) {
+	t, err := http.ParseTime(resp.Header.Get("Memento-Datetime"))
+	return t, err == nil
+}
+
+// Mementos is a list of mementos, ordered by datetime, as MementoLinks
+// holds them, for navigating between them.
+type Mementos []Memento
+
+// First returns the earliest memento. ok is false if there are none.
+func (m Mementos) First() (memento Memento, ok bool) {
+	if len(m) == 0 {
+		return Memento{}, false
+	}
+	return m[0], true
+}
+
+// Last returns the latest memento. ok is false if there are none.
+func (m Mementos) Last() (memento Memento, ok bool) {
+	if len(m) == 0 {
+		return Memento{}, false
+	}
+	return m[len(m)-1], true
+}
+
+// Before returns the latest memento archived before t, the previous one to a
+// memento archived at t. ok is false if there is none.
+func (m Mementos) Before(t time.Time) (memento Memento, ok bool) {
+	i := sort.Search(len(m), func(i int) bool {
+		return !m[i].Datetime.Before(t)
+	})
+	if i == 0 {
+		return Memento{}, false
+	}
+	return m[i-1], true
+}
+
+// After returns the earliest memento archived after t, the next one to a
+// memento archived at t. ok is false if there is none.
+func (m Mementos) After(t time.Time) (memento Memento, ok bool) {
+	i := sort.Search(len(m), func(i int) bool {
+		return m[i].Datetime.After(t)
+	})
+	if i == len(m) {
+		return Memento{}, false
+	}
+	return m[i], true
+}
+
+// Closest returns the memento archived closest to t, the one a TimeGate
+// would serve for it, the earlier on a tie. ok is false if there are none.
+func (m Mementos) Closest(t time.Time) (memento Memento, ok bool) {
+	before, hasBefore := m.Before(t)
+	i := sort.Search(len(m), func(i int) bool {
+		return !m[i].Datetime.Before(t)
+	})
+	if i == len(m) {
+		return before, hasBefore
+	}
+	if !hasBefore || m[i].Datetime.Sub(t) < t.Sub(before.Datetime) {
+		return m[i], true
+	}
+	return before, true
+}
@@ -0,0 +1,101 @@
+package webLinks_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/conslo/webLinks"
+)
+
+// timeMap is a TimeMap, in link-format, as described in RFC 7089, section
+// 5.1.1, with its mementos out of order.
+const timeMap = `<http://a.example.org>;rel="original",
+<http://arxiv.example.net/timemap/http://a.example.org>; rel="self";type="application/link-format";
+  from="Tue, 20 Jun 2000 18:02:59 GMT"; until="Wed, 09 Apr 2008 20:30:51 GMT",
+<http://arxiv.example.net/timegate/http://a.example.org>; rel="timegate",
+<http://arxiv.example.net/web/20080409203051/http://a.example.org>; rel="last memento"; datetime="Wed, 09 Apr 2008 20:30:51 GMT",
+<http://arxiv.example.net/web/20000620180259/http://a.example.org>; rel="first memento"; datetime="Tue, 20 Jun 2000 18:02:59 GMT",
+<http://arxiv.example.net/web/20091027204954/http://a.example.org>; rel="memento"; datetime="not a date",
+<http://arxiv.example.net/web/20000621011731/http://a.example.org>; rel="memento"; datetime="Wed, 21 Jun 2000 01:17:31 GMT",
+<http://arxiv.example.net/timemap/2>; rel="timemap"; type="application/link-format"; from="Thu, 10 Apr 2008 00:00:00 GMT"`
+
+func parseTimeMap(t *testing.T) webLinks.Links {
+	links, err := webLinks.ParseTimeMap([]byte(timeMap))
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	return links
+}
+
+func date(s string) time.Time {
+	t, err := http.ParseTime(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestLinksMementos(t *testing.T) {
+	t.Parallel()
+	m := parseTimeMap(t).Mementos()
+	if m.Original != "http://a.example.org" || m.TimeGate != "http://arxiv.example.net/timegate/http://a.example.org" {
+		t.Fatalf("Got original %q and timegate %q\n", m.Original, m.TimeGate)
+	}
+	if len(m.TimeMaps) != 1 || !m.TimeMaps[0].From.Equal(date("Thu, 10 Apr 2008 00:00:00 GMT")) || !m.TimeMaps[0].Until.IsZero() {
+		t.Fatalf("Got timemaps %+v\n", m.TimeMaps)
+	}
+	if len(m.Mementos) != 3 {
+		t.Fatalf("Expected 3 mementos, got %+v\n", m.Mementos)
+	}
+	for i := 1; i < len(m.Mementos); i++ {
+		if m.Mementos[i].Datetime.Before(m.Mementos[i-1].Datetime) {
+			t.Fatalf("Expected the mementos to be ordered, got %+v\n", m.Mementos)
+		}
+	}
+	if m := webLinks.Parse(`</a>; rel=next`).Mementos(); m.Original != "" || m.Mementos != nil {
+		t.Fatalf("Got %+v\n", m)
+	}
+}
+
+func TestMementosNavigation(t *testing.T) {
+	t.Parallel()
+	m := parseTimeMap(t).Mementos().Mementos
+	first, _ := m.First()
+	last, _ := m.Last()
+	if !first.Datetime.Equal(date("Tue, 20 Jun 2000 18:02:59 GMT")) || !last.Datetime.Equal(date("Wed, 09 Apr 2008 20:30:51 GMT")) {
+		t.Fatalf("Got first %v and last %v\n", first.Datetime, last.Datetime)
+	}
+	if next, ok := m.After(first.Datetime); !ok || next.Link.URI != "http://arxiv.example.net/web/20000621011731/http://a.example.org" {
+		t.Fatalf("Got next %+v\n", next)
+	}
+	if prev, ok := m.Before(last.Datetime); !ok || !prev.Datetime.Equal(date("Wed, 21 Jun 2000 01:17:31 GMT")) {
+		t.Fatalf("Got prev %+v\n", prev)
+	}
+	if _, ok := m.Before(first.Datetime); ok {
+		t.Fatalf("Expected nothing before the first\n")
+	}
+	if _, ok := m.After(last.Datetime); ok {
+		t.Fatalf("Expected nothing after the last\n")
+	}
+	if closest, _ := m.Closest(date("Mon, 01 Jan 2007 00:00:00 GMT")); closest.Link.URI != last.Link.URI {
+		t.Fatalf("Got closest %+v\n", closest)
+	}
+	if closest, _ := m.Closest(date("Mon, 01 Jan 1990 00:00:00 GMT")); closest.Link.URI != first.Link.URI {
+		t.Fatalf("Got closest %+v\n", closest)
+	}
+	if _, ok := webLinks.Mementos(nil).Closest(time.Now()); ok {
+		t.Fatalf("Expected no memento\n")
+	}
+}
+
+func TestMementoDatetime(t *testing.T) {
+	t.Parallel()
+	resp := &http.Response{Header: http.Header{"Memento-Datetime": {"Wed, 30 May 2007 18:47:52 GMT"}}}
+	if datetime, ok := webLinks.MementoDatetime(resp); !ok || !datetime.Equal(date("Wed, 30 May 2007 18:47:52 GMT")) {
+		t.Fatalf("Got %v, %v\n", datetime, ok)
+	}
+	if _, ok := webLinks.MementoDatetime(&http.Response{Header: http.Header{}}); ok {
+		t.Fatalf("Expected no datetime\n")
+	}
+}
@@ -0,0 +1,180 @@
+package webLinks
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// A Page describes the page of a collection a server responds with, for its
+// Links method to link to the others. Pages are numbered, as GitHub's API
+// numbers them, unless the collection is paged by cursor.
+type Page struct {
+	Number  int // the page, counting from 1
+	PerPage int // how many items each page holds, or 0 to leave it unsaid
+	// Total is how many items the collection holds, for linking to the last
+	// page, if TotalKnown is set. Otherwise More says whether there is a next
+	// page.
+	Total      int
+	TotalKnown bool
+	More       bool
+	// Cursor, if set, is the cursor of the next page, for collections paged
+	// by cursor rather than number, which only get first and next links.
+	Cursor string
+}
+
+// Links returns the first, prev, next and last links of the page of a
+// collection at u, as a server should send with it, linking to u with its
+// "page" and "per_page" query parameters set to each page's, or its "cursor"
+// query parameter for collections paged by cursor. Any other query
+// parameters are kept. The links' targets are relative to u's host, so that
+// they are resolved against whichever the client used.
+func (p Page) Links(u *url.URL) Links {
+	target := func(set func(url.Values)) string {
+		query := u.Query()
+		set(query)
+		ref := url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: query.Encode()}
+		return ref.String()
+	}
+	if p.Cursor != "" {
+		first := target(func(query url.Values) {
+			query.Del("cursor")
+		})
+		next := target(func(query url.Values) {
+			query.Set("cursor", p.Cursor)
+		})
+		return Links{
+			BuildLink(first).Rel("first").Link(),
+			BuildLink(next).Rel("next").Link(),
+		}
+	}
+	page := func(n int) string {
+		return target(func(query url.Values) {
+			query.Set("page", strconv.Itoa(n))
+			if p.PerPage > 0 {
+				query.Set("per_page", strconv.Itoa(p.PerPage))
+			}
+		})
+	}
+	last := 0
+	if p.TotalKnown && p.PerPage > 0 {
+		last = (p.Total + p.PerPage - 1) / p.PerPage
+		if last == 0 {
+			// An empty collection still has a page
+			last = 1
+		}
+	}
+	links := Links{BuildLink(page(1)).Rel("first").Link()}
+	if p.Number > 1 {
+		links = append(links, BuildLink(page(p.Number-1)).Rel("prev").Link())
+	}
+	if last > 0 && p.Number < last || last == 0 && p.More {
+		links = append(links, BuildLink(page(p.Number+1)).Rel("next").Link())
+	}
+	if last > 0 {
+		links = append(links, BuildLink(page(last)).Rel("last").Link())
+	}
+	for i := range links {
+		links[i].Index = i
+	}
+	return links
+}
+
+// RequestedPage returns the page number and size that r asks for in its
+// "page" and "per_page" query parameters, as the Links of a Page give them.
+// Pages that aren't given, or aren't positive numbers, are taken to be the
+// first, and sizes to be perPage, as are those larger than max, if max is
+// positive.
+func RequestedPage(r *http.Request, perPage, max int) (page, size int) {
+	query := r.URL.Query()
+	page, size = 1, perPage
+	if n, err := strconv.Atoi(query.Get("page")); err == nil && n > 0 {
+		page = n
+	}
+	if n, err := strconv.Atoi(query.Get("per_page")); err == nil && n > 0 && (max <= 0 || n <= max) {
+		size = n
+	}
+	return page, size
+}
+
+type pageKey struct{}
+
+// Paginate is middleware that sets the pagination links of the page its
+// handler responds with on the response, once the handler has said which
+// page that is with SetPage. The links are those that Page.Links returns for
+// the requested URL, added to any the handler sets itself just before the
+// response is written, or once the handler returns if it writes nothing.
+func Paginate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pw := &pageWriter{ResponseWriter: w, url: r.URL}
+		h.ServeHTTP(pw, r.WithContext(context.WithValue(r.Context(), pageKey{}, pw)))
+		// For handlers that write nothing, leaving the response to be sent
+		// once they return
+		pw.writeLinks()
+	})
+}
+
+// SetPage says which page of a collection the handler of r responds with,
+// for Paginate to link to the others. It must be called before the response
+// is written, and reports whether r came through Paginate. See
+// SetPageLinks for handlers that don't.
+func SetPage(r *http.Request, page Page) bool {
+	pw, ok := r.Context().Value(pageKey{}).(*pageWriter)
+	if ok {
+		pw.page = &page
+	}
+	return ok
+}
+
+// SetPageLinks adds the pagination links of the page of a collection the
+// handler of r responds with to w, as Paginate does.
+func SetPageLinks(w http.ResponseWriter, r *http.Request, page Page) {
+	addPageLinks(w.Header(), r.URL, page)
+}
+
+// addPageLinks adds the links of page, a page of the collection at u, to h,
+// each in a field of its own.
+func addPageLinks(h http.Header, u *url.URL, page Page) {
+	for _, link := range page.Links(u) {
+		// Built from a URL, so there is nothing to validate
+		h.Add("Link", link.String())
+	}
+}
+
+// pageWriter is the http.ResponseWriter Paginate hands its handler.
+type pageWriter struct {
+	http.ResponseWriter
+	url     *url.URL
+	page    *Page
+	written bool
+}
+
+func (w *pageWriter) WriteHeader(status int) {
+	if status >= 200 {
+		// Not an interim response, like 103 Early Hints
+		w.writeLinks()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *pageWriter) Write(b []byte) (int, error) {
+	w.writeLinks()
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the http.ResponseWriter wrapped, for
+// http.ResponseController.
+func (w *pageWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *pageWriter) writeLinks() {
+	if w.written {
+		return
+	}
+	w.written = true
+	if w.page != nil {
+		addPageLinks(w.Header(), w.url, *w.page)
+	}
+}
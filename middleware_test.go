@@ -0,0 +1,107 @@
+package webLinks_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+var pageLinksTests = []struct {
+	url  string
+	page webLinks.Page
+	want string
+}{
+	{"http://api.example.com/items?q=go&page=2", webLinks.Page{Number: 2, PerPage: 10, Total: 45, TotalKnown: true}, `</items?page=1&per_page=10&q=go>; rel=first, </items?page=1&per_page=10&q=go>; rel=prev, ` +
+		`</items?page=3&per_page=10&q=go>; rel=next, </items?page=5&per_page=10&q=go>; rel=last`},
+	{"http://api.example.com/items?q=go&page=2", webLinks.Page{Number: 5, PerPage: 10, Total: 45, TotalKnown: true}, `</items?page=1&per_page=10&q=go>; rel=first, </items?page=4&per_page=10&q=go>; rel=prev, ` +
+		`</items?page=5&per_page=10&q=go>; rel=last`},
+	{"http://api.example.com/items?q=go&page=2", webLinks.Page{Number: 1, PerPage: 10, Total: 0, TotalKnown: true}, `</items?page=1&per_page=10&q=go>; rel=first, </items?page=1&per_page=10&q=go>; rel=last`},
+	{"http://api.example.com/items?q=go&page=2", webLinks.Page{Number: 1, More: true}, `</items?page=1&q=go>; rel=first, </items?page=2&q=go>; rel=next`},
+	{"http://api.example.com/items?q=go&page=2", webLinks.Page{Number: 3}, `</items?page=1&q=go>; rel=first, </items?page=2&q=go>; rel=prev`},
+	{"http://api.example.com/items?q=go&page=2", webLinks.Page{Number: 2, PerPage: 30, More: true}, `</items?page=1&per_page=30&q=go>; rel=first, ` +
+		`</items?page=1&per_page=30&q=go>; rel=prev, </items?page=3&per_page=30&q=go>; rel=next`},
+	{"http://api.example.com/items?q=go&cursor=old", webLinks.Page{Cursor: "abc"}, `</items?q=go>; rel=first, </items?cursor=abc&q=go>; rel=next`},
+}
+
+func TestPageLinks(t *testing.T) {
+	t.Parallel()
+	for _, test := range pageLinksTests {
+		u, _ := url.Parse(test.url)
+		if got := test.page.Links(u).String(); got != test.want {
+			t.Fatalf("Got %s\nexpected %s\nfor %+v\n", got, test.want, test.page)
+		}
+	}
+}
+
+func TestRequestedPage(t *testing.T) {
+	t.Parallel()
+	page, size := webLinks.RequestedPage(httptest.NewRequest("GET", "/items?page=3&per_page=50", nil), 20, 100)
+	if page != 3 || size != 50 {
+		t.Fatalf("Got page %d of %d\n", page, size)
+	}
+	page, size = webLinks.RequestedPage(httptest.NewRequest("GET", "/items?page=-1&per_page=500", nil), 20, 100)
+	if page != 1 || size != 20 {
+		t.Fatalf("Got page %d of %d\n", page, size)
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+	h := webLinks.Paginate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, size := webLinks.RequestedPage(r, 10, 0)
+		if !webLinks.SetPage(r, webLinks.Page{Number: page, PerPage: size, Total: 25, TotalKnown: true}) {
+			t.Errorf("Expected the request to come through Paginate\n")
+		}
+		w.Header().Add("Link", `</items/schema>; rel=describedby`)
+		w.WriteHeader(http.StatusEarlyHints)
+		w.Write([]byte("items"))
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	resp, err := srv.Client().Get(srv.URL + "/items?page=3")
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	resp.Body.Close()
+	links := webLinks.ParseHeader(resp.Header)
+	if len(links) != 4 || !links[0].HasRel("describedby") || !links[2].HasRel("prev") || !links[3].HasRel("last") {
+		t.Fatalf("Got %v\n", links)
+	}
+	if links[3].URI != "/items?page=3&per_page=10" {
+		t.Fatalf("Got last page %q\n", links[3].URI)
+	}
+
+	if webLinks.SetPage(httptest.NewRequest("GET", "/items", nil), webLinks.Page{}) {
+		t.Fatalf("Expected SetPage to fail without Paginate\n")
+	}
+}
+
+func TestPaginateWritesNothing(t *testing.T) {
+	t.Parallel()
+	h := webLinks.Paginate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		webLinks.SetPage(r, webLinks.Page{Number: 1, More: true})
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	resp, err := srv.Client().Get(srv.URL + "/items")
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	resp.Body.Close()
+	links := webLinks.ParseHeader(resp.Header)
+	if resp.StatusCode != http.StatusOK || len(links) != 2 || links[1].URI != "/items?page=2" {
+		t.Fatalf("Got %d with %v\n", resp.StatusCode, links)
+	}
+}
+
+func TestSetPageLinks(t *testing.T) {
+	t.Parallel()
+	rec := httptest.NewRecorder()
+	webLinks.SetPageLinks(rec, httptest.NewRequest("GET", "/items", nil), webLinks.Page{Number: 1, More: true})
+	if values := rec.Header().Values("Link"); len(values) != 2 || values[1] != `</items?page=2>; rel=next` {
+		t.Fatalf("Got %q\n", values)
+	}
+}
@@ -0,0 +1,95 @@
+package webLinks
+
+import "net/url"
+
+// An Option configures a Parser, for Parse and NewParser. Each sets one of
+// the Parser's fields, which is documented with it.
+type Option func(*Parser)
+
+// NewParser returns a Parser configured by opts.
+func NewParser(opts ...Option) *Parser {
+	p := &Parser{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithStrict makes the Parser strict. See Parser.Strict.
+func WithStrict() Option {
+	return func(p *Parser) {
+		p.Strict = true
+	}
+}
+
+// WithLimits sets the Parser's limits: how long a header may be in bytes,
+// how many links it may hold and how many parameters each link may have.
+// Zero means no limit. See Parser.MaxLength.
+func WithLimits(maxLength, maxLinks, maxParams int) Option {
+	return func(p *Parser) {
+		p.MaxLength, p.MaxLinks, p.MaxParams = maxLength, maxLinks, maxParams
+	}
+}
+
+// WithBase resolves link targets and anchors against base. See Parser.Base.
+func WithBase(base *url.URL) Option {
+	return func(p *Parser) {
+		p.Base = base
+	}
+}
+
+// WithDefaultCharset sets the charset assumed for values that don't state
+// theirs. See Parser.DefaultCharset.
+func WithDefaultCharset(name string) Option {
+	return func(p *Parser) {
+		p.DefaultCharset = name
+	}
+}
+
+// WithUTF8Only rejects extended values in any charset other than UTF-8. See
+// Parser.UTF8Only.
+func WithUTF8Only() Option {
+	return func(p *Parser) {
+		p.UTF8Only = true
+	}
+}
+
+// WithRegisteredRels flags relation types that aren't registered. See
+// Parser.RegisteredRels.
+func WithRegisteredRels() Option {
+	return func(p *Parser) {
+		p.RegisteredRels = true
+	}
+}
+
+// WithLossless keeps links as they appeared, for formatting them back exactly.
+// See Parser.Lossless.
+func WithLossless() Option {
+	return func(p *Parser) {
+		p.Lossless = true
+	}
+}
+
+// WithBareTargets recognizes links whose target isn't enclosed in angle
+// brackets. See Parser.BareTargets.
+func WithBareTargets() Option {
+	return func(p *Parser) {
+		p.BareTargets = true
+	}
+}
+
+// WithNFC normalizes decoded values to Unicode Normalization Form C. See
+// Parser.NFC.
+func WithNFC() Option {
+	return func(p *Parser) {
+		p.NFC = true
+	}
+}
+
+// WithKeepCase keeps parameter names spelt as they were. See
+// Parser.KeepCase.
+func WithKeepCase() Option {
+	return func(p *Parser) {
+		p.KeepCase = true
+	}
+}
@@ -0,0 +1,86 @@
+package webLinks_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestParseOptions(t *testing.T) {
+	t.Parallel()
+	input := `<a>; rel=next, <b>; rel=prev; foo, <c>; rel=last`
+	if links := webLinks.Parse(input); len(links) != 3 {
+		t.Fatalf("Expected 3 links without options, got %d\n", len(links))
+	}
+	if links := webLinks.Parse(input, webLinks.WithStrict()); len(links) != 2 {
+		t.Fatalf("Expected the nonconforming link to be dropped when strict, got %+v\n", links)
+	}
+	if links := webLinks.Parse(input, webLinks.WithLimits(0, 3, 0)); len(links) != 3 {
+		t.Fatalf("Expected 3 links with a limit of 3, got %d\n", len(links))
+	}
+	if links := webLinks.Parse(input, webLinks.WithLimits(0, 2, 0)); len(links) != 0 {
+		t.Fatalf("Expected no links for a header over the limit, got %+v\n", links)
+	}
+	if links := webLinks.Parse(input, webLinks.WithLimits(10, 0, 0)); len(links) != 0 {
+		t.Fatalf("Expected no links for a header over the limit, got %+v\n", links)
+	}
+}
+
+func TestParseWithBase(t *testing.T) {
+	t.Parallel()
+	base, _ := url.Parse("https://example.com/a/b")
+	links := webLinks.Parse(`<c>; rel=next; anchor="#x", </d>; rel=prev`, webLinks.WithBase(base))
+	expected := []string{"https://example.com/a/c", "https://example.com/d"}
+	for i, link := range links {
+		if link.URI != expected[i] {
+			t.Fatalf("URI mismatch at %d, got %q expected %q\n", i, link.URI, expected[i])
+		}
+	}
+	if anchor, _ := links[0].Anchor(); anchor != "https://example.com/a/b#x" {
+		t.Fatalf("Expected the anchor to be resolved, got %q\n", anchor)
+	}
+
+	d := webLinks.NewDecoder(strings.NewReader(`<c>; rel=next`))
+	d.Base = base
+	link, err := d.Next()
+	if err != nil || link.URI != "https://example.com/a/c" {
+		t.Fatalf("Expected the decoder to resolve too, got %q, %v\n", link.URI, err)
+	}
+}
+
+var defaultCharsetTests = []struct {
+	input string
+	value string
+	enc   string
+}{
+	{"</a>; rel=next; title=\"n\xe4chstes\"", "nächstes", "iso-8859-1"},
+	{"</a>; rel=next; title*=n%E4chstes", "nächstes", "iso-8859-1"},
+	// Stated charsets win
+	{"</a>; rel=next; title*=UTF-8''n%C3%A4chstes", "nächstes", "UTF-8"},
+	// ASCII is left alone
+	{"</a>; rel=next; title=next", "next", "us-ascii"},
+}
+
+func TestParseWithDefaultCharset(t *testing.T) {
+	t.Parallel()
+	for _, test := range defaultCharsetTests {
+		links := webLinks.Parse(test.input, webLinks.WithDefaultCharset("iso-8859-1"))
+		if len(links) != 1 {
+			t.Fatalf("Expected a link for %q, got %+v\n", test.input, links)
+		}
+		param, _ := links[0].Param("title")
+		if param.Value != test.value || param.Enc != test.enc {
+			t.Fatalf("Got %+v expected %q in %q for %q\n", param, test.value, test.enc, test.input)
+		}
+	}
+}
+
+func TestNewParser(t *testing.T) {
+	t.Parallel()
+	p := webLinks.NewParser(webLinks.WithUTF8Only(), webLinks.WithBareTargets(), webLinks.WithNFC(), webLinks.WithKeepCase())
+	if !p.UTF8Only || !p.BareTargets || !p.NFC || !p.KeepCase || p.Strict {
+		t.Fatalf("Options not applied, got %+v\n", p)
+	}
+}
@@ -0,0 +1,505 @@
+package webLinks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pagination holds the targets of the links APIs use to page through a
+// collection, or nil for those that are absent.
+type Pagination struct {
+	Next  *url.URL
+	Prev  *url.URL
+	First *url.URL
+	Last  *url.URL
+}
+
+// Pagination returns the targets of the first next, prev, first and last
+// links, as found by First. "previous" is taken for "prev" if there is no
+// prev link. Targets that can't be parsed as URLs are left out, just as if
+// the link was absent. They aren't resolved; see FromResponse for that.
+func (l Links) Pagination() Pagination {
+	target := func(rels ...string) *url.URL {
+		for _, rel := range rels {
+			if link, ok := l.First(rel); ok {
+				u, err := link.URL()
+				if err != nil {
+					return nil
+				}
+				return u
+			}
+		}
+		return nil
+	}
+	return Pagination{
+		Next:  target("next"),
+		Prev:  target("prev", "previous"),
+		First: target("first"),
+		Last:  target("last"),
+	}
+}
+
+// PageNumbers holds the numbers of the pages that pagination links lead to,
+// as APIs like GitHub's and GitLab's give them in the "page" query parameter
+// of their targets, along with the page size they give in "per_page". Those
+// that aren't given are 0.
+type PageNumbers struct {
+	Next, Prev, First, Last int
+	PerPage                 int
+
+	more bool // whether there is a next link, whatever its target
+}
+
+// PageNumbers returns the page numbers in the targets of the links'
+// pagination links, as found by Pagination. The page size is taken from
+// whichever of them gives one, next first.
+func (l Links) PageNumbers() PageNumbers {
+	pages := l.Pagination()
+	n := PageNumbers{
+		Next:  queryInt(pages.Next, "page"),
+		Prev:  queryInt(pages.Prev, "page"),
+		First: queryInt(pages.First, "page"),
+		Last:  queryInt(pages.Last, "page"),
+		more:  pages.Next != nil,
+	}
+	for _, u := range []*url.URL{pages.Next, pages.Prev, pages.First, pages.Last} {
+		if n.PerPage = queryInt(u, "per_page"); n.PerPage != 0 {
+			break
+		}
+	}
+	return n
+}
+
+// queryInt returns the query parameter of u called name as a positive
+// number, or 0 if it doesn't hold one, or u is nil.
+func queryInt(u *url.URL, name string) int {
+	if u == nil {
+		return 0
+	}
+	n, err := strconv.Atoi(u.Query().Get(name))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Current returns the number of the page the links were found on, the one
+// before next or after prev, or 0 if neither says.
+func (n PageNumbers) Current() int {
+	switch {
+	case n.Next > 0:
+		return n.Next - 1
+	case n.Prev > 0:
+		return n.Prev + 1
+	}
+	return 0
+}
+
+// Total returns how many pages there are: the number of the last, or of the
+// current page if there is no next one. It returns 0 if the links don't say.
+func (n PageNumbers) Total() int {
+	switch {
+	case n.Last > 0:
+		return n.Last
+	case !n.more:
+		return n.Current()
+	}
+	return 0
+}
+
+// Remaining returns how many pages there are after the current one, for
+// reporting progress, or -1 if the links don't say. There are none if there
+// is no next link, as on GitHub's last page, which has no last link either.
+func (n PageNumbers) Remaining() int {
+	switch {
+	case !n.more:
+		return 0
+	case n.Last > 0 && n.Current() > 0:
+		return n.Last - n.Current()
+	}
+	return -1
+}
+
+// cursorParams are the query parameters Cursor looks for by default, those
+// that cursor-based APIs most often use.
+var cursorParams = []string{"cursor", "page_token", "pageToken", "after", "next_cursor", "starting_after"}
+
+// Cursor returns the cursor in the target of the links' next link, as APIs
+// that page by cursor rather than number give it, so that clients can store
+// it and resume from it later with CursorURL. It is the value of the first of
+// the query parameters names that the target has, or if no names are given,
+// of the first of "cursor", "page_token", "pageToken", "after",
+// "next_cursor" and "starting_after" it has. name is the parameter it was
+// found in. ok is false if there is no next link, or it has none of them.
+func (l Links) Cursor(names ...string) (name, cursor string, ok bool) {
+	next := l.Pagination().Next
+	if next == nil {
+		return "", "", false
+	}
+	if len(names) == 0 {
+		names = cursorParams
+	}
+	query := next.Query()
+	for _, name := range names {
+		if values, found := query[name]; found && len(values) > 0 {
+			return name, values[0], true
+		}
+	}
+	return "", "", false
+}
+
+// CursorURL returns a copy of u with its query parameter called name set to
+// cursor, as found by Cursor, for resuming paging through a collection at u
+// where it was left off. Any other query parameters are kept.
+func CursorURL(u *url.URL, name, cursor string) *url.URL {
+	resumed := *u
+	query := u.Query()
+	query.Set(name, cursor)
+	resumed.RawQuery = query.Encode()
+	return &resumed
+}
+
+// A Paginator fetches the pages of a paginated collection one at a time,
+// following the rel=next link in the "Link" header of each page to the next,
+// as APIs like GitHub's have clients do.
+type Paginator struct {
+	client *http.Client
+	first  *http.Request
+	next   *http.Request
+	links  Links
+	err    error
+
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	workers int
+	ahead   *ahead
+}
+
+// A PageOption configures a Paginator.
+type PageOption func(*Paginator)
+
+// Retry makes the Paginator try fetching a page up to attempts more times
+// when it fails transiently: when the request fails, as it does when the
+// network does, or the response has a 5xx status, or 429 Too Many Requests.
+// It waits backoff before the first retry, and twice as long before each
+// after that, up to max, unless the response says how long to wait with a
+// Retry-After header. Waiting is cut short if the request's context is done.
+// Requests whose body can't be sent again, lacking a GetBody, aren't retried.
+func Retry(attempts int, backoff, max time.Duration) PageOption {
+	return func(p *Paginator) {
+		p.retries, p.backoff, p.maxBackoff = attempts, backoff, max
+	}
+}
+
+// Concurrently makes the Paginator fetch the pages after the first up to
+// workers at a time, whenever the first page's next and last links say which
+// there are: when they are the same URL but for a query parameter holding a
+// page number, like those of GitHub's API. Pages are still returned in order,
+// and no more than workers are fetched ahead of the one Next returns. The
+// pages are fetched with the context of the Paginator's request, and stop
+// being so once fetching one fails or Close is called.
+func Concurrently(workers int) PageOption {
+	return func(p *Paginator) {
+		p.workers = workers
+	}
+}
+
+// NewPaginator returns a Paginator that fetches the first page with req, and
+// the ones after it with GET requests to the next links it finds, all using
+// client, or http.DefaultClient if client is nil, and configured by opts.
+// The later requests carry req's headers as long as they go to the same
+// host, with the same scheme, so that credentials aren't handed to another
+// or sent over plain http.
+func NewPaginator(client *http.Client, req *http.Request, opts ...PageOption) *Paginator {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	p := &Paginator{client: client, first: req, next: req}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Next fetches the next page, with the context of the request the Paginator
+// was made with. See NextContext.
+func (p *Paginator) Next() (*http.Response, error) {
+	return p.NextContext(p.first.Context())
+}
+
+// NextContext fetches the next page with ctx, returning io.EOF once the last,
+// the first without a next link, has been returned. The caller must close the
+// body of each response. Responses are returned whatever their status, though
+// error responses seldom have a next link. Once fetching a page fails, or ctx
+// is done before it could be, NextContext keeps returning the same error.
+func (p *Paginator) NextContext(ctx context.Context) (*http.Response, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.ahead != nil {
+		return p.nextAhead(ctx)
+	}
+	if p.next == nil {
+		return nil, io.EOF
+	}
+	resp, err := p.fetch(ctx, p.next)
+	if err != nil {
+		p.err = err
+		return nil, err
+	}
+	p.links, _ = FromResponse(resp)
+	p.next = p.nextRequest(p.links)
+	if p.workers > 0 && p.next != nil {
+		p.fetchAhead()
+	}
+	return resp, nil
+}
+
+// Close stops the Paginator fetching pages ahead, as Concurrently has it do,
+// and closes the bodies of any it fetched that Next hasn't returned. Next
+// returns io.EOF after it. Close needn't be called once Next has returned
+// an error, io.EOF included, and always returns nil.
+func (p *Paginator) Close() error {
+	a := p.ahead
+	p.ahead, p.next = nil, nil
+	if a == nil {
+		return nil
+	}
+	a.cancel()
+	<-a.done
+	for i := a.i; i < a.started; i++ {
+		if page := <-a.results[i%len(a.results)]; page.resp != nil {
+			page.resp.Body.Close()
+		}
+	}
+	return nil
+}
+
+// ahead holds the pages being fetched ahead of Next.
+type ahead struct {
+	pages int
+	// The result of fetching page i goes in results[i%workers], which is
+	// free by then, as window only lets workers pages be fetched ahead
+	results []chan page
+	window  chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{} // closed once no more pages will be fetched
+	started int           // pages fetched, or being fetched, once done
+	i       int           // the next page for Next to return
+}
+
+type page struct {
+	resp *http.Response
+	err  error
+}
+
+// fetchAhead starts fetching the pages after the current one concurrently,
+// if its next and last links say which there are.
+func (p *Paginator) fetchAhead() {
+	pages := p.links.Pagination()
+	name, from, to, ok := pageParam(pages.Next, pages.Last)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(p.first.Context())
+	a := &ahead{
+		pages:   to - from + 1,
+		results: make([]chan page, p.workers),
+		window:  make(chan struct{}, p.workers),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	for i := range a.results {
+		a.results[i] = make(chan page, 1)
+	}
+	next := *pages.Next
+	go func() {
+		defer close(a.done)
+		for i := 0; i < a.pages; i++ {
+			select {
+			case a.window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			u := next
+			query := u.Query()
+			query.Set(name, strconv.Itoa(from+i))
+			u.RawQuery = query.Encode()
+			req := p.requestFor(&u)
+			a.started++
+			go func(i int) {
+				resp, err := p.fetch(ctx, req)
+				a.results[i%len(a.results)] <- page{resp, err}
+			}(i)
+		}
+	}()
+	p.ahead, p.next = a, nil
+}
+
+// nextAhead returns the next of the pages being fetched ahead.
+func (p *Paginator) nextAhead(ctx context.Context) (*http.Response, error) {
+	a := p.ahead
+	if a.i == a.pages {
+		// Every page has been returned, so fetching them is over with
+		p.Close()
+		return nil, io.EOF
+	}
+	var page page
+	select {
+	case page = <-a.results[a.i%len(a.results)]:
+		a.i++
+		<-a.window
+	case <-ctx.Done():
+		// The page is left for Close to discard
+		page.err = ctx.Err()
+	}
+	if page.err != nil {
+		p.Close()
+		p.err = page.err
+		return nil, page.err
+	}
+	p.links, _ = FromResponse(page.resp)
+	return page.resp, nil
+}
+
+// pageParam finds the query parameter that holds the page number in the
+// URLs of the next and last pages, which must be the same but for it,
+// returning their numbers. ok is false if they aren't.
+func pageParam(next, last *url.URL) (name string, from, to int, ok bool) {
+	if next == nil || last == nil || !next.IsAbs() {
+		return "", 0, 0, false
+	}
+	a, b := *next, *last
+	a.RawQuery, b.RawQuery = "", ""
+	if a.String() != b.String() {
+		return "", 0, 0, false
+	}
+	qa, qb := next.Query(), last.Query()
+	if len(qa) != len(qb) {
+		return "", 0, 0, false
+	}
+	for key, values := range qa {
+		other, found := qb[key]
+		if !found || len(values) != len(other) {
+			return "", 0, 0, false
+		}
+		if len(values) == 1 && values[0] == other[0] {
+			continue
+		}
+		x, errX := strconv.Atoi(qa.Get(key))
+		y, errY := strconv.Atoi(qb.Get(key))
+		if name != "" || len(values) != 1 || errX != nil || errY != nil || x > y {
+			return "", 0, 0, false
+		}
+		name, from, to = key, x, y
+	}
+	if name == "" {
+		// Already on the last page but one
+		return "", 0, 0, false
+	}
+	return name, from, to, true
+}
+
+// Links returns the links of the page Next last returned, with their targets
+// resolved, or nil before the first page.
+func (p *Paginator) Links() Links {
+	return p.links
+}
+
+// fetch sends req with ctx, retrying as configured.
+func (p *Paginator) fetch(ctx context.Context, req *http.Request) (*http.Response, error) {
+	rewindable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	backoff := p.backoff
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		r := req.WithContext(ctx)
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+		resp, err := p.client.Do(r)
+		if attempt == p.retries || !rewindable || ctx.Err() != nil || !transient(resp, err) {
+			return resp, err
+		}
+
+		wait := backoff
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				wait = after
+			}
+			// Drained so that the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if wait > p.maxBackoff {
+			wait = p.maxBackoff
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// transient reports whether a request that got resp and err might succeed if
+// tried again.
+func transient(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// retryAfter returns how long resp's Retry-After header says to wait before
+// trying again, if it has one.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	after := resp.Header.Get("Retry-After")
+	if after == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(after); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(after); err == nil {
+		return time.Until(t), true
+	}
+	return 0, false
+}
+
+// nextRequest returns the request for the page the links say is next, or nil
+// if there is none.
+func (p *Paginator) nextRequest(links Links) *http.Request {
+	next := links.Pagination().Next
+	if next == nil || !next.IsAbs() {
+		return nil
+	}
+	return p.requestFor(next)
+}
+
+// requestFor returns the request for the page at next, an absolute URL.
+func (p *Paginator) requestFor(next *url.URL) *http.Request {
+	req := p.first.Clone(p.first.Context())
+	req.Method, req.URL, req.Host = http.MethodGet, next, ""
+	req.Body, req.GetBody, req.ContentLength = nil, nil, 0
+	if next.Host != p.first.URL.Host || !strings.EqualFold(next.Scheme, p.first.URL.Scheme) {
+		// Nor sent in the clear, if the first page was fetched over https
+		req.Header = http.Header{}
+	}
+	return req
+}
@@ -0,0 +1,478 @@
+package webLinks_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestLinksPagination(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`<https://api.example.com/items?page=3>; rel=next, <https://api.example.com/items?page=9>; rel=last, ` +
+		`<https://api.example.com/items?page=1>; rel="first previous"`)
+	pages := links.Pagination()
+	if pages.Next == nil || pages.Next.Query().Get("page") != "3" {
+		t.Fatalf("Got next %v\n", pages.Next)
+	}
+	if pages.Last == nil || pages.Last.Query().Get("page") != "9" {
+		t.Fatalf("Got last %v\n", pages.Last)
+	}
+	if pages.First == nil || pages.Prev == nil || pages.First.String() != pages.Prev.String() {
+		t.Fatalf("Got first %v and prev %v\n", pages.First, pages.Prev)
+	}
+}
+
+func TestLinksPaginationAbsent(t *testing.T) {
+	t.Parallel()
+	pages := webLinks.Parse(`</items?page=2>; rel=prev, <http://[bad>; rel=next`).Pagination()
+	if pages.Next != nil || pages.First != nil || pages.Last != nil {
+		t.Fatalf("Got %+v\n", pages)
+	}
+	if pages.Prev == nil || pages.Prev.String() != "/items?page=2" {
+		t.Fatalf("Got prev %v\n", pages.Prev)
+	}
+	if pages := webLinks.Links(nil).Pagination(); pages != (webLinks.Pagination{}) {
+		t.Fatalf("Got %+v\n", pages)
+	}
+}
+
+var pageNumbersTests = []struct {
+	header                  string
+	current, total, remains int
+	perPage                 int
+}{
+	{`<https://api.example.com/items?page=3&per_page=50>; rel=next, <https://api.example.com/items?page=9&per_page=50>; rel=last`, 2, 9, 7, 50},
+	{`<https://api.example.com/items?page=8>; rel=prev, <https://api.example.com/items?page=1>; rel=first`, 9, 9, 0, 0},
+	{`<https://api.example.com/items?cursor=abc>; rel=next`, 0, 0, -1, 0},
+	{`<https://api.example.com/items?page=x>; rel=next, <https://api.example.com/items?page=4>; rel=last`, 0, 4, -1, 0},
+	{``, 0, 0, 0, 0},
+}
+
+func TestLinksPageNumbers(t *testing.T) {
+	t.Parallel()
+	for _, test := range pageNumbersTests {
+		n := webLinks.Parse(test.header).PageNumbers()
+		if n.Current() != test.current || n.Total() != test.total || n.Remaining() != test.remains || n.PerPage != test.perPage {
+			t.Fatalf("Got current %d, total %d, remaining %d and %d per page for %q\n", n.Current(), n.Total(), n.Remaining(), n.PerPage, test.header)
+		}
+	}
+	n := webLinks.Parse(`</items?page=3>; rel=next, </items?page=2>; rel=prev, </items?page=1>; rel=first, </items?page=9>; rel=last`).PageNumbers()
+	if n.Next != 3 || n.Prev != 2 || n.First != 1 || n.Last != 9 {
+		t.Fatalf("Got %+v\n", n)
+	}
+}
+
+var cursorTests = []struct {
+	header string
+	names  []string
+	name   string
+	cursor string
+	ok     bool
+}{
+	{`<https://api.example.com/items?cursor=abc%3D&limit=10>; rel=next`, nil, "cursor", "abc=", true},
+	{`<https://api.example.com/items?pageToken=xyz>; rel=next`, nil, "pageToken", "xyz", true},
+	{`<https://api.example.com/items?after=1&cursor=2>; rel=next`, nil, "cursor", "2", true},
+	{`<https://api.example.com/items?after=1&cursor=2>; rel=next`, []string{"after"}, "after", "1", true},
+	{`<https://api.example.com/items?token=t>; rel=next`, []string{"token"}, "token", "t", true},
+	{`<https://api.example.com/items?page=2>; rel=next`, nil, "", "", false},
+	{`<https://api.example.com/items?cursor=abc>; rel=prev`, nil, "", "", false},
+}
+
+func TestLinksCursor(t *testing.T) {
+	t.Parallel()
+	for _, test := range cursorTests {
+		name, cursor, ok := webLinks.Parse(test.header).Cursor(test.names...)
+		if name != test.name || cursor != test.cursor || ok != test.ok {
+			t.Fatalf("Got %q, %q, %v for %q\n", name, cursor, ok, test.header)
+		}
+	}
+}
+
+func TestCursorURL(t *testing.T) {
+	t.Parallel()
+	u, _ := url.Parse("https://api.example.com/items?limit=10&cursor=old")
+	resumed := webLinks.CursorURL(u, "cursor", "a b")
+	if resumed.String() != "https://api.example.com/items?cursor=a+b&limit=10" {
+		t.Fatalf("Got %v\n", resumed)
+	}
+	if u.Query().Get("cursor") != "old" {
+		t.Fatalf("Expected u to be left alone, got %v\n", u)
+	}
+}
+
+// pagedServer serves a collection of pages items, linking each to the next,
+// and records the requests it got.
+func pagedServer(t *testing.T, pages int) (*httptest.Server, *[]*http.Request) {
+	var mu sync.Mutex
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r)
+		mu.Unlock()
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page < pages {
+			w.Header().Add("Link", fmt.Sprintf(`</items?page=%d>; rel=next`, page+1))
+		}
+		w.Header().Add("Link", fmt.Sprintf(`</items?page=%d>; rel=last`, pages))
+		fmt.Fprintf(w, "page %d", page)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestPaginator(t *testing.T) {
+	t.Parallel()
+	srv, requests := pagedServer(t, 3)
+	req, _ := http.NewRequest("POST", srv.URL+"/items", strings.NewReader("query"))
+	req.Header.Set("Authorization", "token")
+	p := webLinks.NewPaginator(srv.Client(), req)
+	if p.Links() != nil {
+		t.Fatalf("Expected no links before the first page\n")
+	}
+	var bodies []string
+	for {
+		resp, err := p.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		bodies = append(bodies, string(body))
+	}
+	if strings.Join(bodies, ", ") != "page 1, page 2, page 3" {
+		t.Fatalf("Got %q\n", bodies)
+	}
+	if last, ok := p.Links().First("last"); !ok || last.URI != srv.URL+"/items?page=3" {
+		t.Fatalf("Expected the last page's links, resolved, got %v\n", p.Links())
+	}
+	for i, r := range *requests {
+		if i > 0 && (r.Method != "GET" || r.Header.Get("Authorization") != "token") {
+			t.Fatalf("Request %d mismatch, got %s with %v\n", i, r.Method, r.Header)
+		}
+	}
+	if _, err := p.Next(); err != io.EOF {
+		t.Fatalf("Expected io.EOF to be returned again, got %v\n", err)
+	}
+}
+
+func TestPaginatorOtherHost(t *testing.T) {
+	t.Parallel()
+	other, requests := pagedServer(t, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", "<"+other.URL+"/items>; rel=next")
+	}))
+	defer srv.Close()
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	req.Header.Set("Authorization", "token")
+	p := webLinks.NewPaginator(nil, req)
+	for i := 0; i < 2; i++ {
+		resp, err := p.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		resp.Body.Close()
+	}
+	if len(*requests) != 1 || (*requests)[0].Header.Get("Authorization") != "" {
+		t.Fatalf("Expected credentials not to be sent to another host\n")
+	}
+}
+
+// roundTripper answers requests with the Link header field values in links,
+// by URL, recording their Authorization headers.
+type roundTripper struct {
+	links map[string]string
+	auth  []string
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.auth = append(rt.auth, req.Header.Get("Authorization"))
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}
+	if link, ok := rt.links[req.URL.String()]; ok {
+		resp.Header.Set("Link", link)
+	}
+	return resp, nil
+}
+
+func TestPaginatorOtherScheme(t *testing.T) {
+	t.Parallel()
+	rt := &roundTripper{links: map[string]string{
+		"https://api.example.com/items": "<http://api.example.com/items?page=2>; rel=next",
+	}}
+	req, _ := http.NewRequest("GET", "https://api.example.com/items", nil)
+	req.Header.Set("Authorization", "token")
+	p := webLinks.NewPaginator(&http.Client{Transport: rt}, req)
+	for i := 0; i < 2; i++ {
+		if _, err := p.Next(); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+	}
+	if len(rt.auth) != 2 || rt.auth[0] != "token" || rt.auth[1] != "" {
+		t.Fatalf("Expected credentials not to be sent over http, got %q\n", rt.auth)
+	}
+}
+
+func TestPaginatorError(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close()
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	p := webLinks.NewPaginator(nil, req)
+	_, err := p.Next()
+	if err == nil || err == io.EOF {
+		t.Fatalf("Expected an error, got %v\n", err)
+	}
+	if _, again := p.Next(); again != err {
+		t.Fatalf("Expected the same error, got %v\n", again)
+	}
+}
+
+// flakyServer fails the first failures requests it gets with status, then
+// serves a single page, echoing the request's body.
+func flakyServer(t *testing.T, failures, status int) (*httptest.Server, *int32) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if atomic.AddInt32(&requests, 1) <= int32(failures) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestPaginatorRetry(t *testing.T) {
+	t.Parallel()
+	for _, status := range []int{http.StatusServiceUnavailable, http.StatusTooManyRequests} {
+		srv, requests := flakyServer(t, 2, status)
+		req, _ := http.NewRequest("POST", srv.URL, strings.NewReader("query"))
+		p := webLinks.NewPaginator(srv.Client(), req, webLinks.Retry(3, time.Millisecond, 10*time.Millisecond))
+		resp, err := p.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || string(body) != "query" || atomic.LoadInt32(requests) != 3 {
+			t.Fatalf("Got %s %q after %d requests\n", resp.Status, body, atomic.LoadInt32(requests))
+		}
+	}
+}
+
+func TestPaginatorRetryExhausted(t *testing.T) {
+	t.Parallel()
+	srv, requests := flakyServer(t, 5, http.StatusBadGateway)
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	resp, err := webLinks.NewPaginator(srv.Client(), req, webLinks.Retry(2, 0, 0)).Next()
+	if err != nil || resp.StatusCode != http.StatusBadGateway || atomic.LoadInt32(requests) != 3 {
+		t.Fatalf("Expected the last response after 3 requests, got %v %v after %d\n", resp, err, atomic.LoadInt32(requests))
+	}
+	resp.Body.Close()
+
+	srv, requests = flakyServer(t, 1, http.StatusNotFound)
+	req, _ = http.NewRequest("GET", srv.URL, nil)
+	resp, err = webLinks.NewPaginator(srv.Client(), req, webLinks.Retry(2, 0, 0)).Next()
+	if err != nil || resp.StatusCode != http.StatusNotFound || atomic.LoadInt32(requests) != 1 {
+		t.Fatalf("Expected client errors not to be retried, got %v %v after %d\n", resp, err, atomic.LoadInt32(requests))
+	}
+	resp.Body.Close()
+}
+
+func TestPaginatorRetryCanceled(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	p := webLinks.NewPaginator(srv.Client(), req, webLinks.Retry(5, time.Hour, time.Hour))
+	start := time.Now()
+	if _, err := p.NextContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to be exceeded, got %v\n", err)
+	}
+	if time.Since(start) > time.Minute {
+		t.Fatalf("Expected waiting to be cut short\n")
+	}
+}
+
+func TestPaginatorConcurrently(t *testing.T) {
+	t.Parallel()
+	var inFlight, most int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&most)
+			if n <= m || atomic.CompareAndSwapInt32(&most, m, n) {
+				break
+			}
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		w.Header().Add("Link", `</items?page=2&per_page=5>; rel=next, </items?page=10&per_page=5>; rel=last`)
+		time.Sleep(5 * time.Millisecond)
+		fmt.Fprintf(w, "page %d", page)
+	}))
+	defer srv.Close()
+	req, _ := http.NewRequest("GET", srv.URL+"/items?per_page=5", nil)
+	p := webLinks.NewPaginator(srv.Client(), req, webLinks.Concurrently(3))
+	defer p.Close()
+	for want := 1; ; want++ {
+		resp, err := p.Next()
+		if err == io.EOF {
+			if want != 11 {
+				t.Fatalf("Expected 10 pages, got %d\n", want-1)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("Page %d: %v\n", want, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != fmt.Sprintf("page %d", want) {
+			t.Fatalf("Expected page %d, got %q\n", want, body)
+		}
+	}
+	if most := atomic.LoadInt32(&most); most < 2 || most > 3 {
+		t.Fatalf("Expected up to 3 pages fetched at once, got %d\n", most)
+	}
+}
+
+func TestPaginatorConcurrentlyUnknownLast(t *testing.T) {
+	t.Parallel()
+	// Next and last differ in more than the page number
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Add("Link", `</items?cursor=abc>; rel=next, </items?cursor=xyz>; rel=last`)
+		}
+	}))
+	defer srv.Close()
+	req, _ := http.NewRequest("GET", srv.URL+"/items", nil)
+	p := webLinks.NewPaginator(srv.Client(), req, webLinks.Concurrently(4))
+	defer p.Close()
+	pages := 0
+	for {
+		_, err := p.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error %v\n", err)
+		}
+		pages++
+	}
+	if pages != 2 {
+		t.Fatalf("Expected the next links to be followed one by one, got %d pages\n", pages)
+	}
+}
+
+func TestPaginatorConcurrentlyEOF(t *testing.T) {
+	t.Parallel()
+	srv, _ := pagedServer(t, 4)
+	req, _ := http.NewRequest("GET", srv.URL+"/items", nil)
+	p := webLinks.NewPaginator(srv.Client(), req, webLinks.Concurrently(2))
+	var last *http.Response
+	for {
+		resp, err := p.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error %v\n", err)
+		}
+		resp.Body.Close()
+		last = resp
+	}
+	// Without Close, the context the pages were fetched with is done with
+	if err := last.Request.Context().Err(); err != context.Canceled {
+		t.Fatalf("Expected the pages' context to be canceled, got %v\n", err)
+	}
+}
+
+func TestPaginatorConcurrentlyError(t *testing.T) {
+	t.Parallel()
+	srv, _ := pagedServer(t, 8)
+	req, _ := http.NewRequest("GET", srv.URL+"/items", nil)
+	p := webLinks.NewPaginator(srv.Client(), req, webLinks.Concurrently(2))
+	if _, err := p.Next(); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	srv.Close()
+	if _, err := p.Next(); err == nil {
+		t.Fatalf("Expected an error once the server is gone\n")
+	}
+	if _, err := p.Next(); err == nil {
+		t.Fatalf("Expected the error to stick\n")
+	}
+}
+
+func TestPaginatorClose(t *testing.T) {
+	t.Parallel()
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Add("Link", `</items?page=2>; rel=next, </items?page=100>; rel=last`)
+	}))
+	defer srv.Close()
+	req, _ := http.NewRequest("GET", srv.URL+"/items", nil)
+	p := webLinks.NewPaginator(srv.Client(), req, webLinks.Concurrently(2))
+	for i := 0; i < 2; i++ {
+		resp, err := p.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error %v\n", err)
+		}
+		resp.Body.Close()
+	}
+	p.Close()
+	if _, err := p.Next(); err != io.EOF {
+		t.Fatalf("Expected io.EOF after closing, got %v\n", err)
+	}
+	// The first page, the one returned, and up to two fetched ahead
+	if n := atomic.LoadInt32(&requests); n > 4 {
+		t.Fatalf("Expected fetching to stop, got %d requests\n", n)
+	}
+}
+
+func TestPaginatorCloseUnstarted(t *testing.T) {
+	t.Parallel()
+	srv, _ := pagedServer(t, 3)
+	req, _ := http.NewRequest("GET", srv.URL+"/items", nil)
+	p := webLinks.NewPaginator(srv.Client(), req)
+	if _, err := p.Next(); err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	p.Close()
+	if _, err := p.Next(); err != io.EOF {
+		t.Fatalf("Expected io.EOF after closing, got %v\n", err)
+	}
+}
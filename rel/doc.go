@@ -0,0 +1,29 @@
+// Package rel provides constants for the link relation types registered
+// with IANA, as described in RFC 8288, section 2.1.1, so that they needn't
+// be spelt out as strings:
+//
+//	links.First(rel.Next)
+//
+// The constants are generated from the registry, at
+// https://www.iana.org/assignments/link-relations.
+package rel
+
+import (
+	"sort"
+	"strings"
+)
+
+//go:generate go run gen.go
+
+// All returns every registered relation type, sorted.
+func All() []string {
+	return append([]string(nil), all...)
+}
+
+// Registered reports whether rel is a registered relation type. Relation
+// types are compared case-insensitively, as RFC 8288, section 2.1.1 requires.
+func Registered(rel string) bool {
+	rel = strings.ToLower(rel)
+	i := sort.SearchStrings(all, rel)
+	return i < len(all) && all[i] == rel
+}
@@ -0,0 +1,141 @@
+//go:build ignore
+
+// gen generates rel.go from the IANA Link Relations registry. It fetches the
+// registry unless given the path of a copy of its CSV on the command line.
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+const registry = "https://www.iana.org/assignments/link-relations/link-relations-1.csv"
+
+// names holds the Go names of relation types that are several words run
+// together, or initialisms, which can't be told from their spelling.
+var names = map[string]string{
+	"acl":                     "ACL",
+	"amphtml":                 "AMPHTML",
+	"api-catalog":             "APICatalog",
+	"c2pa-manifest":           "C2PAManifest",
+	"cite-as":                 "CiteAs",
+	"convertedfrom":           "ConvertedFrom",
+	"describedby":             "DescribedBy",
+	"dns-prefetch":            "DNSPrefetch",
+	"ice-server":              "ICEServer",
+	"intervalafter":           "IntervalAfter",
+	"intervalbefore":          "IntervalBefore",
+	"intervalcontains":        "IntervalContains",
+	"intervaldisjoint":        "IntervalDisjoint",
+	"intervalduring":          "IntervalDuring",
+	"intervalequals":          "IntervalEquals",
+	"intervalfinishedby":      "IntervalFinishedBy",
+	"intervalfinishes":        "IntervalFinishes",
+	"intervalin":              "IntervalIn",
+	"intervalmeets":           "IntervalMeets",
+	"intervalmetby":           "IntervalMetBy",
+	"intervaloverlappedby":    "IntervalOverlappedBy",
+	"intervaloverlaps":        "IntervalOverlaps",
+	"intervalstartedby":       "IntervalStartedBy",
+	"intervalstarts":          "IntervalStarts",
+	"linkset":                 "LinkSet",
+	"lrdd":                    "LRDD",
+	"modulepreload":           "ModulePreload",
+	"nofollow":                "NoFollow",
+	"noopener":                "NoOpener",
+	"noreferrer":              "NoReferrer",
+	"openid2.local_id":        "OpenID2LocalID",
+	"openid2.provider":        "OpenID2Provider",
+	"p3pv1":                   "P3Pv1",
+	"rdap-active":             "RDAPActive",
+	"rdap-bottom":             "RDAPBottom",
+	"rdap-down":               "RDAPDown",
+	"rdap-top":                "RDAPTop",
+	"rdap-up":                 "RDAPUp",
+	"restconf":                "RESTCONF",
+	"ruleinput":               "RuleInput",
+	"sip-trunking-capability": "SIPTrunkingCapability",
+	"timegate":                "TimeGate",
+	"timemap":                 "TimeMap",
+	"ugc":                     "UGC",
+}
+
+func main() {
+	r, err := open()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer r.Close()
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var rels []string
+	for _, record := range records[1:] {
+		if rel := strings.TrimSpace(record[0]); rel != "" {
+			rels = append(rels, rel)
+		}
+	}
+	sort.Strings(rels)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen.go from the IANA Link Relations registry; DO NOT EDIT.\n\n")
+	b.WriteString("package rel\n\n")
+	b.WriteString("// The relation types registered with IANA.\nconst (\n")
+	for _, rel := range rels {
+		fmt.Fprintf(&b, "%s = %q\n", name(rel), rel)
+	}
+	b.WriteString(")\n\n")
+	b.WriteString("// all holds every registered relation type, sorted.\nvar all = []string{\n")
+	for _, rel := range rels {
+		fmt.Fprintf(&b, "%s,\n", name(rel))
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("rel.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// open returns the registry, from the file named on the command line if any.
+func open() (io.ReadCloser, error) {
+	if len(os.Args) > 1 {
+		return os.Open(os.Args[1])
+	}
+	resp, err := http.Get(registry)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetching %s: %s", registry, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// name returns the Go name of the constant for rel.
+func name(rel string) string {
+	if name, ok := names[rel]; ok {
+		return name
+	}
+	words := strings.FieldsFunc(rel, func(r rune) bool {
+		return r == '-' || r == '.' || r == '_'
+	})
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, "")
+}
@@ -0,0 +1,277 @@
+// Code generated by gen.go from the IANA Link Relations registry; DO NOT EDIT.
+
+package rel
+
+// The relation types registered with IANA.
+const (
+	About                  = "about"
+	ACL                    = "acl"
+	Alternate              = "alternate"
+	AMPHTML                = "amphtml"
+	APICatalog             = "api-catalog"
+	Appendix               = "appendix"
+	AppleTouchIcon         = "apple-touch-icon"
+	AppleTouchStartupImage = "apple-touch-startup-image"
+	Archives               = "archives"
+	Author                 = "author"
+	BlockedBy              = "blocked-by"
+	Bookmark               = "bookmark"
+	C2PAManifest           = "c2pa-manifest"
+	Canonical              = "canonical"
+	Chapter                = "chapter"
+	CiteAs                 = "cite-as"
+	Collection             = "collection"
+	CompressionDictionary  = "compression-dictionary"
+	Contents               = "contents"
+	ConvertedFrom          = "convertedfrom"
+	Copyright              = "copyright"
+	CreateForm             = "create-form"
+	Current                = "current"
+	Deprecation            = "deprecation"
+	DescribedBy            = "describedby"
+	Describes              = "describes"
+	Disclosure             = "disclosure"
+	DNSPrefetch            = "dns-prefetch"
+	Duplicate              = "duplicate"
+	Edit                   = "edit"
+	EditForm               = "edit-form"
+	EditMedia              = "edit-media"
+	Enclosure              = "enclosure"
+	External               = "external"
+	First                  = "first"
+	Geofeed                = "geofeed"
+	Glossary               = "glossary"
+	Help                   = "help"
+	Hosts                  = "hosts"
+	Hub                    = "hub"
+	ICEServer              = "ice-server"
+	Icon                   = "icon"
+	Index                  = "index"
+	IntervalAfter          = "intervalafter"
+	IntervalBefore         = "intervalbefore"
+	IntervalContains       = "intervalcontains"
+	IntervalDisjoint       = "intervaldisjoint"
+	IntervalDuring         = "intervalduring"
+	IntervalEquals         = "intervalequals"
+	IntervalFinishedBy     = "intervalfinishedby"
+	IntervalFinishes       = "intervalfinishes"
+	IntervalIn             = "intervalin"
+	IntervalMeets          = "intervalmeets"
+	IntervalMetBy          = "intervalmetby"
+	IntervalOverlappedBy   = "intervaloverlappedby"
+	IntervalOverlaps       = "intervaloverlaps"
+	IntervalStartedBy      = "intervalstartedby"
+	IntervalStarts         = "intervalstarts"
+	Item                   = "item"
+	Last                   = "last"
+	LatestVersion          = "latest-version"
+	License                = "license"
+	LinkSet                = "linkset"
+	LRDD                   = "lrdd"
+	Manifest               = "manifest"
+	MaskIcon               = "mask-icon"
+	Me                     = "me"
+	MediaFeed              = "media-feed"
+	Memento                = "memento"
+	Micropub               = "micropub"
+	ModulePreload          = "modulepreload"
+	Monitor                = "monitor"
+	MonitorGroup           = "monitor-group"
+	Next                   = "next"
+	NextArchive            = "next-archive"
+	NoFollow               = "nofollow"
+	NoOpener               = "noopener"
+	NoReferrer             = "noreferrer"
+	Opener                 = "opener"
+	OpenID2LocalID         = "openid2.local_id"
+	OpenID2Provider        = "openid2.provider"
+	Original               = "original"
+	P3Pv1                  = "p3pv1"
+	Payment                = "payment"
+	Pingback               = "pingback"
+	Preconnect             = "preconnect"
+	PredecessorVersion     = "predecessor-version"
+	Prefetch               = "prefetch"
+	Preload                = "preload"
+	Prerender              = "prerender"
+	Prev                   = "prev"
+	PrevArchive            = "prev-archive"
+	Preview                = "preview"
+	Previous               = "previous"
+	PrivacyPolicy          = "privacy-policy"
+	Profile                = "profile"
+	Publication            = "publication"
+	RDAPActive             = "rdap-active"
+	RDAPBottom             = "rdap-bottom"
+	RDAPDown               = "rdap-down"
+	RDAPTop                = "rdap-top"
+	RDAPUp                 = "rdap-up"
+	Related                = "related"
+	Replies                = "replies"
+	RESTCONF               = "restconf"
+	RuleInput              = "ruleinput"
+	Search                 = "search"
+	Section                = "section"
+	Self                   = "self"
+	Service                = "service"
+	ServiceDesc            = "service-desc"
+	ServiceDoc             = "service-doc"
+	ServiceMeta            = "service-meta"
+	SIPTrunkingCapability  = "sip-trunking-capability"
+	Sponsored              = "sponsored"
+	Start                  = "start"
+	Status                 = "status"
+	Stylesheet             = "stylesheet"
+	Subsection             = "subsection"
+	SuccessorVersion       = "successor-version"
+	Sunset                 = "sunset"
+	Tag                    = "tag"
+	TermsOfService         = "terms-of-service"
+	TimeGate               = "timegate"
+	TimeMap                = "timemap"
+	Type                   = "type"
+	UGC                    = "ugc"
+	Up                     = "up"
+	VersionHistory         = "version-history"
+	Via                    = "via"
+	Webmention             = "webmention"
+	WorkingCopy            = "working-copy"
+	WorkingCopyOf          = "working-copy-of"
+)
+
+// all holds every registered relation type, sorted.
+var all = []string{
+	About,
+	ACL,
+	Alternate,
+	AMPHTML,
+	APICatalog,
+	Appendix,
+	AppleTouchIcon,
+	AppleTouchStartupImage,
+	Archives,
+	Author,
+	BlockedBy,
+	Bookmark,
+	C2PAManifest,
+	Canonical,
+	Chapter,
+	CiteAs,
+	Collection,
+	CompressionDictionary,
+	Contents,
+	ConvertedFrom,
+	Copyright,
+	CreateForm,
+	Current,
+	Deprecation,
+	DescribedBy,
+	Describes,
+	Disclosure,
+	DNSPrefetch,
+	Duplicate,
+	Edit,
+	EditForm,
+	EditMedia,
+	Enclosure,
+	External,
+	First,
+	Geofeed,
+	Glossary,
+	Help,
+	Hosts,
+	Hub,
+	ICEServer,
+	Icon,
+	Index,
+	IntervalAfter,
+	IntervalBefore,
+	IntervalContains,
+	IntervalDisjoint,
+	IntervalDuring,
+	IntervalEquals,
+	IntervalFinishedBy,
+	IntervalFinishes,
+	IntervalIn,
+	IntervalMeets,
+	IntervalMetBy,
+	IntervalOverlappedBy,
+	IntervalOverlaps,
+	IntervalStartedBy,
+	IntervalStarts,
+	Item,
+	Last,
+	LatestVersion,
+	License,
+	LinkSet,
+	LRDD,
+	Manifest,
+	MaskIcon,
+	Me,
+	MediaFeed,
+	Memento,
+	Micropub,
+	ModulePreload,
+	Monitor,
+	MonitorGroup,
+	Next,
+	NextArchive,
+	NoFollow,
+	NoOpener,
+	NoReferrer,
+	Opener,
+	OpenID2LocalID,
+	OpenID2Provider,
+	Original,
+	P3Pv1,
+	Payment,
+	Pingback,
+	Preconnect,
+	PredecessorVersion,
+	Prefetch,
+	Preload,
+	Prerender,
+	Prev,
+	PrevArchive,
+	Preview,
+	Previous,
+	PrivacyPolicy,
+	Profile,
+	Publication,
+	RDAPActive,
+	RDAPBottom,
+	RDAPDown,
+	RDAPTop,
+	RDAPUp,
+	Related,
+	Replies,
+	RESTCONF,
+	RuleInput,
+	Search,
+	Section,
+	Self,
+	Service,
+	ServiceDesc,
+	ServiceDoc,
+	ServiceMeta,
+	SIPTrunkingCapability,
+	Sponsored,
+	Start,
+	Status,
+	Stylesheet,
+	Subsection,
+	SuccessorVersion,
+	Sunset,
+	Tag,
+	TermsOfService,
+	TimeGate,
+	TimeMap,
+	Type,
+	UGC,
+	Up,
+	VersionHistory,
+	Via,
+	Webmention,
+	WorkingCopy,
+	WorkingCopyOf,
+}
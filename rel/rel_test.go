@@ -0,0 +1,57 @@
+package rel_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/conslo/webLinks/rel"
+)
+
+func TestConstants(t *testing.T) {
+	t.Parallel()
+	for constant, expected := range map[string]string{
+		rel.Next:           "next",
+		rel.Canonical:      "canonical",
+		rel.DescribedBy:    "describedby",
+		rel.PrevArchive:    "prev-archive",
+		rel.OpenID2LocalID: "openid2.local_id",
+	} {
+		if constant != expected {
+			t.Fatalf("Got %q expected %q\n", constant, expected)
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+	all := rel.All()
+	if !sort.StringsAreSorted(all) {
+		t.Fatalf("Expected the relation types to be sorted\n")
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i] == all[i-1] {
+			t.Fatalf("Duplicate relation type %q\n", all[i])
+		}
+	}
+	all[0] = "changed"
+	if rel.All()[0] != rel.About {
+		t.Fatalf("Expected All to return a copy\n")
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	t.Parallel()
+	for input, expected := range map[string]bool{
+		"next":                   true,
+		"DescribedBy":            true,
+		"about":                  true,
+		"working-copy-of":        true,
+		"nextt":                  false,
+		"":                       false,
+		"http://example.com/rel": false,
+	} {
+		if rel.Registered(input) != expected {
+			t.Fatalf("Expected %t for %q\n", expected, input)
+		}
+	}
+}
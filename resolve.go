@@ -0,0 +1,98 @@
+package webLinks
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// URL parses the link's target, returning url.Parse's error if it isn't a
+// valid URI-reference.
+func (l Link) URL() (*url.URL, error) {
+	return url.Parse(l.URI)
+}
+
+// ResolveAll returns a copy of the links whose targets and anchors are
+// resolved against base, as described in RFC 3986, section 5. Targets and
+// anchors that can't be parsed as URI-references are left as they are. The
+// links themselves aren't modified.
+func (l Links) ResolveAll(base *url.URL) Links {
+	links, _ := l.resolve(base)
+	return links
+}
+
+// ResolveResponse returns a copy of the links resolved as ResolveAll does,
+// against the base URI of resp, as ResponseBase finds it. It suits links
+// that came with resp some other way than its Link headers, which FromResponse
+// already resolves. The links are returned as they are if resp has no base.
+func (l Links) ResolveResponse(resp *http.Response) Links {
+	base := ResponseBase(resp)
+	if base == nil {
+		return l
+	}
+	return l.ResolveAll(base)
+}
+
+// resolve is ResolveAll, also reporting which targets and anchors couldn't be
+// parsed.
+func (l Links) resolve(base *url.URL) (Links, error) {
+	if l == nil {
+		return nil, nil
+	}
+	links := make(Links, len(l))
+	var errs []error
+	for i, link := range l {
+		var err error
+		if links[i], err = link.resolve(base); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return links, errors.Join(errs...)
+}
+
+// resolve returns a copy of the link whose target and anchor are resolved
+// against base.
+func (l Link) resolve(base *url.URL) (Link, error) {
+	var errs []error
+	if ref, err := l.URL(); err == nil {
+		l.URI = base.ResolveReference(ref).String()
+	} else {
+		errs = append(errs, err)
+	}
+	if anchor, ok := l.Anchor(); ok {
+		if ref, err := url.Parse(anchor); err == nil {
+			l = l.withParam("anchor", base.ResolveReference(ref).String())
+		} else {
+			errs = append(errs, err)
+		}
+	}
+	return l, errors.Join(errs...)
+}
+
+// normalURI normalizes the URI-reference s, as described in RFC 3986,
+// section 6.2.2, so that equivalent URIs compare equal: its scheme and host
+// are lowercased, default ports dropped, unneeded percent-encoding decoded
+// and, for URIs with an authority, dot segments removed and an empty path
+// made "/". References that can't be parsed are returned as they are.
+func normalURI(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return s
+	}
+	u.Host = strings.ToLower(u.Host)
+	if port := u.Port(); port == "80" && u.Scheme == "http" || port == "443" && u.Scheme == "https" {
+		u.Host = strings.TrimSuffix(u.Host, ":"+port)
+	}
+	if !strings.Contains(strings.ToUpper(u.RawPath), "%2F") {
+		// Encoded slashes are the only thing the raw path tells apart
+		u.RawPath = ""
+	}
+	if u.Host != "" || u.IsAbs() {
+		u = u.ResolveReference(&url.URL{Fragment: u.Fragment, RawFragment: u.RawFragment})
+		if u.Host != "" && u.Path == "" {
+			u.Path = "/"
+		}
+	}
+	return u.String()
+}
@@ -0,0 +1,86 @@
+package webLinks_test
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestLinksResolveAll(t *testing.T) {
+	t.Parallel()
+	base, _ := url.Parse("https://example.com/dir/page?x=1")
+	links := webLinks.Parse(`</abs>; rel=a, <rel>; rel=b, <?page=2>; rel=next, <https://other.example/x>; rel=c, <../up>; rel=d; anchor="#frag", <http://[bad>; rel=e; anchor="other"`)
+	resolved := links.ResolveAll(base)
+	expected := []struct {
+		uri    string
+		anchor string
+	}{
+		{"https://example.com/abs", ""},
+		{"https://example.com/dir/rel", ""},
+		{"https://example.com/dir/page?page=2", ""},
+		{"https://other.example/x", ""},
+		{"https://example.com/up", "https://example.com/dir/page?x=1#frag"},
+		{"http://[bad", "https://example.com/dir/other"},
+	}
+	if len(resolved) != len(expected) {
+		t.Fatalf("Length mismatch, got %d expected %d\n", len(resolved), len(expected))
+	}
+	for i, link := range resolved {
+		anchor, _ := link.Anchor()
+		if link.URI != expected[i].uri || anchor != expected[i].anchor {
+			t.Fatalf("Got %q %q expected %q %q\n", link.URI, anchor, expected[i].uri, expected[i].anchor)
+		}
+	}
+	// The original links are left alone
+	if links[1].URI != "rel" {
+		t.Fatalf("Got %q expected %q\n", links[1].URI, "rel")
+	}
+	if anchor, _ := links[4].Anchor(); anchor != "#frag" {
+		t.Fatalf("Got %q expected %q\n", anchor, "#frag")
+	}
+	if rel := resolved[4].Ordered[1]; rel.Name != "anchor" || rel.Value != "https://example.com/dir/page?x=1#frag" {
+		t.Fatalf("Expected Ordered to follow, got %+v\n", rel)
+	}
+}
+
+func TestLinksResolveAllFormatted(t *testing.T) {
+	t.Parallel()
+	base, _ := url.Parse("https://example.com/page")
+	// The anchor is in an unknown charset, so would be written as it was
+	links, _ := webLinks.ParseLenient(`</a>; rel=next; anchor*=x'y'%23top`)
+	if output := links.ResolveAll(base).String(); output != `<https://example.com/a>; rel=next; anchor="https://example.com/page#top"` {
+		t.Fatalf("Got %s\n", output)
+	}
+}
+
+func TestLinksResolveResponse(t *testing.T) {
+	t.Parallel()
+	req, _ := http.NewRequest("GET", "https://example.com/a/b", nil)
+	resp := &http.Response{Header: http.Header{"Content-Location": {"/c/d"}}, Request: req}
+	links := webLinks.Parse(`<e>; rel=next`).ResolveResponse(resp)
+	if links[0].URI != "https://example.com/c/e" {
+		t.Fatalf("Got %q\n", links[0].URI)
+	}
+	if links := webLinks.Parse(`<e>; rel=next`).ResolveResponse(&http.Response{}); links[0].URI != "e" {
+		t.Fatalf("Got %q\n", links[0].URI)
+	}
+}
+
+func TestLinkURL(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`<https://example.com/items?page=2>; rel=next, <http://[bad>; rel=prev`)
+	u, err := links[0].URL()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	if u.Host != "example.com" || u.Query().Get("page") != "2" {
+		t.Fatalf("Got %v\n", u)
+	}
+	var uerr *url.Error
+	if u, err := links[1].URL(); !errors.As(err, &uerr) || u != nil {
+		t.Fatalf("Expected a *url.Error, got %v %v\n", u, err)
+	}
+}
@@ -0,0 +1,66 @@
+package webLinks
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ServiceLinks holds the links RFC 8631 defines for describing a web API,
+// as any of its responses may carry them, each in order.
+type ServiceLinks struct {
+	Desc   Links // rel=service-desc: machine-readable descriptions, like OpenAPI
+	Doc    Links // rel=service-doc: documentation for people
+	Meta   Links // rel=service-meta: metadata about the API, like its policies
+	Status Links // rel=status: the API's status, like whether it is up
+}
+
+// Service picks out the links that describe a web API, as RFC 8631 defines
+// them.
+func (l Links) Service() ServiceLinks {
+	return ServiceLinks{
+		Desc:   l.Rel("service-desc"),
+		Doc:    l.Rel("service-doc"),
+		Meta:   l.Rel("service-meta"),
+		Status: l.Rel("status"),
+	}
+}
+
+// ServiceOf returns the links describing the API resp is from, with their
+// targets resolved as FromResponse resolves them.
+func ServiceOf(resp *http.Response) ServiceLinks {
+	// Targets that can't be resolved are left as they were
+	links, _ := FromResponse(resp)
+	return links.Service()
+}
+
+// openAPITypes are the media types of OpenAPI documents, and those of
+// Swagger's, which preceded them.
+var openAPITypes = []string{
+	"application/openapi+json",
+	"application/openapi+yaml",
+	"application/vnd.oai.openapi",
+	"application/vnd.oai.openapi+json",
+	"application/swagger+json",
+}
+
+// OpenAPI returns the first of the service-desc links whose type is that of
+// an OpenAPI document, like "application/vnd.oai.openapi+json", or failing
+// that, the first without a type at all, which may be one. ok is false if
+// there is neither.
+func (s ServiceLinks) OpenAPI() (link Link, ok bool) {
+	var untyped *Link
+	for i, link := range s.Desc {
+		mediatype, _, err := link.MediaType()
+		switch {
+		case err != nil:
+		case mediatype == "" && untyped == nil:
+			untyped = &s.Desc[i]
+		case contains(openAPITypes, strings.ToLower(mediatype)):
+			return link, true
+		}
+	}
+	if untyped != nil {
+		return *untyped, true
+	}
+	return Link{}, false
+}
@@ -0,0 +1,44 @@
+package webLinks_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestLinksService(t *testing.T) {
+	t.Parallel()
+	links := webLinks.Parse(`</schema.graphql>; rel=service-desc; type="application/graphql", ` +
+		`</openapi.json>; rel="service-desc"; type="application/vnd.oai.openapi+json;version=3.1", ` +
+		`</docs>; rel=service-doc, </terms>; rel=service-meta, </health>; rel=status, </a>; rel=next`)
+	s := links.Service()
+	if len(s.Desc) != 2 || len(s.Doc) != 1 || len(s.Meta) != 1 || len(s.Status) != 1 {
+		t.Fatalf("Got %+v\n", s)
+	}
+	if doc, ok := s.OpenAPI(); !ok || doc.URI != "/openapi.json" {
+		t.Fatalf("Got OpenAPI %v\n", doc)
+	}
+
+	s = webLinks.Parse(`</api.yaml>; rel=service-desc, </openapi.json>; rel=service-desc; type=application/openapi+json`).Service()
+	if doc, _ := s.OpenAPI(); doc.URI != "/openapi.json" {
+		t.Fatalf("Expected a typed description to win, got %v\n", doc)
+	}
+	s = webLinks.Parse(`</schema.graphql>; rel=service-desc; type="application/graphql", </api.yaml>; rel=service-desc`).Service()
+	if doc, _ := s.OpenAPI(); doc.URI != "/api.yaml" {
+		t.Fatalf("Expected an untyped description, got %v\n", doc)
+	}
+	if _, ok := webLinks.Parse(`</schema.graphql>; rel=service-desc; type="application/graphql"`).Service().OpenAPI(); ok {
+		t.Fatalf("Expected no OpenAPI description\n")
+	}
+}
+
+func TestServiceOf(t *testing.T) {
+	t.Parallel()
+	req, _ := http.NewRequest("GET", "https://api.example.com/v1/items", nil)
+	resp := &http.Response{Header: http.Header{"Link": {`</v1/openapi.json>; rel=service-desc, <docs>; rel=service-doc`}}, Request: req}
+	s := webLinks.ServiceOf(resp)
+	if len(s.Desc) != 1 || s.Desc[0].URI != "https://api.example.com/v1/openapi.json" || s.Doc[0].URI != "https://api.example.com/v1/docs" {
+		t.Fatalf("Got %+v\n", s)
+	}
+}
@@ -0,0 +1,91 @@
+package webLinks
+
+import (
+	iana "github.com/conslo/webLinks/rel"
+)
+
+// A LandingPage holds the Signposting links of the landing page of a
+// scholarly object, such as an article or a dataset in a repository, as
+// described at https://signposting.org/FAIR/. Targets are "" for the links
+// that are absent.
+type LandingPage struct {
+	CiteAs  string   // the object's persistent identifier, like a DOI
+	Authors []string // the authors' identifiers, like ORCIDs
+	License string
+	// Types are the object's types, like "https://schema.org/ScholarlyArticle",
+	// along with "https://schema.org/AboutPage" for the page itself.
+	Types []string
+	// DescribedBy are the object's metadata records, and Items the content
+	// it is made of, each with the type of its target.
+	DescribedBy Links
+	Items       Links
+	Linksets    Links // linksets holding any of these links, see FetchLinkSets
+}
+
+// LandingPage picks out the Signposting links of a landing page. Only the
+// first cite-as and license links count, as there may only be one of each.
+// Links from a linkset, or with anchors, should first be narrowed down to
+// those about the landing page, with LinkSet.About.
+func (l Links) LandingPage() LandingPage {
+	var p LandingPage
+	if link, ok := l.First(iana.CiteAs); ok {
+		p.CiteAs = link.URI
+	}
+	if link, ok := l.First(iana.License); ok {
+		p.License = link.URI
+	}
+	for _, link := range l.Rel(iana.Author) {
+		p.Authors = append(p.Authors, link.URI)
+	}
+	for _, link := range l.Rel(iana.Type) {
+		p.Types = append(p.Types, link.URI)
+	}
+	p.DescribedBy = l.Rel(iana.DescribedBy)
+	p.Items = l.Rel(iana.Item)
+	p.Linksets = l.Rel(iana.LinkSet)
+	return p
+}
+
+// A Signpost is the Signposting links of a content resource or a metadata
+// record of a scholarly object, linking back to its landing page. Targets
+// are "" for the links that are absent.
+type Signpost struct {
+	// Collection is the landing page of the object a content resource is
+	// part of, and Describes that of the object a metadata record is about.
+	Collection string
+	Describes  string
+	License    string
+	Types      []string
+}
+
+// Signpost picks out the Signposting links of a content resource or a
+// metadata record, which link back to their landing page. Only the first
+// collection, describes and license links count.
+func (l Links) Signpost() Signpost {
+	var s Signpost
+	if link, ok := l.First(iana.Collection); ok {
+		s.Collection = link.URI
+	}
+	if link, ok := l.First(iana.Describes); ok {
+		s.Describes = link.URI
+	}
+	if link, ok := l.First(iana.License); ok {
+		s.License = link.URI
+	}
+	for _, link := range l.Rel(iana.Type) {
+		s.Types = append(s.Types, link.URI)
+	}
+	return s
+}
+
+// LandingPage returns the landing page the resource links back to, its
+// collection or the one it describes. ok is false if it links to neither.
+func (s Signpost) LandingPage() (uri string, ok bool) {
+	switch {
+	case s.Collection != "":
+		return s.Collection, true
+	case s.Describes != "":
+		return s.Describes, true
+	}
+	return "", false
+}
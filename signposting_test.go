@@ -0,0 +1,66 @@
+package webLinks_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+// landingPage is the Link header of a landing page, as in the examples at
+// https://signposting.org/FAIR/.
+const landingPage = `<https://orcid.org/0000-0002-1825-0097>; rel="author", <https://ror.org/02mhbdp94>; rel=author, ` +
+	`<https://doi.org/10.5061/dryad.5d23f>; rel="cite-as", <https://doi.org/10.1000/other>; rel=cite-as, ` +
+	`<https://spdx.org/licenses/CC0-1.0>; rel="license", ` +
+	`<https://schema.org/ScholarlyArticle>; rel="type", <https://schema.org/AboutPage>; rel="type", ` +
+	`<https://example.org/meta.xml>; rel="describedby"; type="application/vnd.datacite.datacite+xml", ` +
+	`<https://example.org/article.pdf>; rel="item"; type="application/pdf", ` +
+	`<https://example.org/data.csv>; rel="item"; type="text/csv", ` +
+	`<https://example.org/linkset>; rel=linkset; type="application/linkset+json"`
+
+func TestLinksLandingPage(t *testing.T) {
+	t.Parallel()
+	p := webLinks.Parse(landingPage).LandingPage()
+	if p.CiteAs != "https://doi.org/10.5061/dryad.5d23f" || p.License != "https://spdx.org/licenses/CC0-1.0" {
+		t.Fatalf("Got cite-as %q and license %q\n", p.CiteAs, p.License)
+	}
+	if len(p.Authors) != 2 || p.Authors[1] != "https://ror.org/02mhbdp94" {
+		t.Fatalf("Got authors %q\n", p.Authors)
+	}
+	if len(p.Types) != 2 || p.Types[0] != "https://schema.org/ScholarlyArticle" {
+		t.Fatalf("Got types %q\n", p.Types)
+	}
+	if len(p.DescribedBy) != 1 || p.DescribedBy[0].Type() != "application/vnd.datacite.datacite+xml" {
+		t.Fatalf("Got describedby %v\n", p.DescribedBy)
+	}
+	if len(p.Items) != 2 || p.Items[1].Type() != "text/csv" || len(p.Linksets) != 1 {
+		t.Fatalf("Got items %v and linksets %v\n", p.Items, p.Linksets)
+	}
+}
+
+func TestLinkSetLandingPage(t *testing.T) {
+	t.Parallel()
+	page, _ := url.Parse("https://example.org/record/1")
+	set := webLinks.NewLinkSet(page, webLinks.Parse(`<https://doi.org/10.1/a>; rel=cite-as; anchor="https://example.org/record/1", `+
+		`<https://example.org/record/1>; rel=collection; anchor="https://example.org/article.pdf"`))
+	p := set.About(page).LandingPage()
+	if p.CiteAs != "https://doi.org/10.1/a" || p.Items != nil {
+		t.Fatalf("Got %+v\n", p)
+	}
+}
+
+func TestLinksSignpost(t *testing.T) {
+	t.Parallel()
+	s := webLinks.Parse(`<https://example.org/record/1>; rel="collection", <https://schema.org/Dataset>; rel=type, ` +
+		`<https://spdx.org/licenses/CC0-1.0>; rel=license`).Signpost()
+	if page, ok := s.LandingPage(); !ok || page != "https://example.org/record/1" || s.License == "" || len(s.Types) != 1 {
+		t.Fatalf("Got %+v\n", s)
+	}
+	s = webLinks.Parse(`<https://example.org/record/2>; rel=describes`).Signpost()
+	if page, ok := s.LandingPage(); !ok || page != "https://example.org/record/2" {
+		t.Fatalf("Got %+v\n", s)
+	}
+	if _, ok := webLinks.Parse(`</a>; rel=next`).Signpost().LandingPage(); ok {
+		t.Fatalf("Expected no landing page\n")
+	}
+}
@@ -0,0 +1,115 @@
+package webLinks
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// A Decoder reads links from a stream one at a time, so that even huge
+// collections of links never have to be held in memory all at once. The
+// stream holds a single "Link" header value, or anything else with the same
+// syntax.
+//
+// The embedded Parser configures how links are parsed, just as it would for
+// (*Parser).Parse.
+type Decoder struct {
+	Parser
+
+	r      *bufio.Reader
+	buf    bytes.Buffer
+	offset int // of the start of buf within the stream
+	links  int // decoded so far
+	err    error
+}
+
+// NewDecoder returns a Decoder reading links from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// Next returns the next link in the stream, or io.EOF once there are none
+// left. Links that are too malformed to be of use are skipped, unless the
+// Decoder is strict, in which case a *SyntaxError is returned and decoding
+// may carry on with the link after it. Offsets in any *SyntaxError are
+// relative to the start of the stream.
+//
+// Limits apply to the stream as a whole: once one is exceeded every call
+// returns an error wrapping ErrLimitExceeded.
+func (d *Decoder) Next() (Link, error) {
+	for {
+		value, err := d.readValue()
+		if value == "" && err != nil {
+			return Link{}, err
+		}
+
+		ps := parser{Parser: d.Parser, s: value, owned: value}
+		if ps.done() {
+			// Empty list element
+			continue
+		}
+		l, ok, perr := ps.link()
+		if errors.Is(perr, ErrLimitExceeded) {
+			d.err = perr
+			return Link{}, perr
+		}
+		if perr != nil {
+			var serr *SyntaxError
+			if errors.As(perr, &serr) {
+				serr.Offset += d.offset - len(value) - 1
+			}
+			return Link{}, perr
+		}
+		if ok {
+			if d.MaxLinks > 0 && d.links == d.MaxLinks {
+				d.err = limitError("stream has more than %d links", d.MaxLinks)
+				return Link{}, d.err
+			}
+			l.Index = d.links
+			d.links++
+			if d.Base != nil {
+				l, _ = l.resolve(d.Base)
+			}
+			return l, nil
+		}
+	}
+}
+
+// readValue reads up to the ',' that ends the next link-value, returning the
+// link-value without it.
+func (d *Decoder) readValue() (string, error) {
+	if d.err != nil {
+		return "", d.err
+	}
+	d.buf.Reset()
+	quoted, escaped, target := false, false, false
+	for {
+		c, err := d.r.ReadByte()
+		if err != nil {
+			d.err = err
+			d.offset += d.buf.Len() + 1
+			return d.buf.String(), nil
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"' && !target:
+			quoted = !quoted
+		case c == '<' && !quoted:
+			target = true
+		case c == '>' && !quoted:
+			target = false
+		case c == ',' && !quoted && !target:
+			d.offset += d.buf.Len() + 1
+			return d.buf.String(), nil
+		}
+		d.buf.WriteByte(c)
+		if d.MaxLength > 0 && d.offset+d.buf.Len() > d.MaxLength {
+			d.err = limitError("stream is longer than %d bytes", d.MaxLength)
+			return "", d.err
+		}
+	}
+}
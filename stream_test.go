@@ -0,0 +1,126 @@
+package webLinks_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+	for _, test := range tests {
+		d := webLinks.NewDecoder(strings.NewReader(test.input))
+		for i := 0; ; i++ {
+			link, err := d.Next()
+			if err == io.EOF {
+				if i != len(test.links) {
+					t.Fatalf("Length mismatch, got %d expected %d\n", i, len(test.links))
+				}
+				break
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error for %q: %s\n", test.input, err)
+			}
+			if i >= len(test.links) {
+				t.Fatalf("Too many links decoded from %q\n", test.input)
+			}
+			if link.URI != test.links[i].URI {
+				t.Fatalf("Got the wrong URI, got %q expected %q\n", link.URI, test.links[i].URI)
+			}
+			for k, v := range test.links[i].Params {
+				if link.Params[k] != v {
+					t.Fatalf("Value mismatch, got %+v expected %+v\n", link.Params[k], v)
+				}
+			}
+		}
+	}
+}
+
+// slowReader hands out a single byte at a time, to make sure nothing
+// depends on how the stream is split up.
+type slowReader struct{ r io.Reader }
+
+func (s slowReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return s.r.Read(p[:1])
+}
+
+func TestDecoderIncremental(t *testing.T) {
+	t.Parallel()
+	input := `<http://example.com/a,b>; rel="next"; title="x, y", , junk, </c>; rel=last`
+	d := webLinks.NewDecoder(slowReader{strings.NewReader(input)})
+	expected := []string{"http://example.com/a,b", "/c"}
+	for _, uri := range expected {
+		link, err := d.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+		if link.URI != uri {
+			t.Fatalf("Got the wrong URI, got %q expected %q\n", link.URI, uri)
+		}
+	}
+	if _, err := d.Next(); err != io.EOF {
+		t.Fatalf("Expected io.EOF, got %v\n", err)
+	}
+}
+
+func TestDecoderStrict(t *testing.T) {
+	t.Parallel()
+	d := webLinks.NewDecoder(strings.NewReader(`</a>; rel=next, </b c>; rel=next, </d>; rel=last`))
+	d.Strict = true
+	if link, err := d.Next(); err != nil || link.URI != "/a" {
+		t.Fatalf("Expected /a, got %v %v\n", link, err)
+	}
+	var serr *webLinks.SyntaxError
+	if _, err := d.Next(); !errors.As(err, &serr) || serr.Offset != 17 {
+		t.Fatalf("Expected a *SyntaxError at offset 17, got %v\n", err)
+	}
+	if link, err := d.Next(); err != nil || link.URI != "/d" {
+		t.Fatalf("Expected /d, got %v %v\n", link, err)
+	}
+}
+
+func TestDecoderLimits(t *testing.T) {
+	t.Parallel()
+	d := webLinks.NewDecoder(strings.NewReader(strings.Repeat(`</a>; rel=next, `, 10)))
+	d.MaxLinks = 3
+	for i := 0; i < 3; i++ {
+		if _, err := d.Next(); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err)
+		}
+	}
+	if _, err := d.Next(); !errors.Is(err, webLinks.ErrLimitExceeded) {
+		t.Fatalf("Expected ErrLimitExceeded, got %v\n", err)
+	}
+
+	d = webLinks.NewDecoder(strings.NewReader(`</a>; rel=next, </` + strings.Repeat("a", 1<<20) + `>`))
+	d.MaxLength = 1024
+	if _, err := d.Next(); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if _, err := d.Next(); !errors.Is(err, webLinks.ErrLimitExceeded) {
+		t.Fatalf("Expected ErrLimitExceeded, got %v\n", err)
+	}
+}
+
+func TestDecoderIndex(t *testing.T) {
+	t.Parallel()
+	d := webLinks.NewDecoder(strings.NewReader(`</a>; rel=next, </b>; rel=prev, </c>; rel=last`))
+	for i := 0; ; i++ {
+		link, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if link.Index != i {
+			t.Fatalf("Got index %d expected %d\n", link.Index, i)
+		}
+	}
+}
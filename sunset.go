@@ -0,0 +1,83 @@
+package webLinks
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Retirement says whether the resource a response is from is deprecated or
+// going away, and where to read about it, as the Deprecation header of
+// RFC 9745 and the Sunset header of RFC 8594 say, along with their
+// rel=deprecation and rel=sunset links.
+type Retirement struct {
+	// Deprecated is set if the resource is deprecated, or is going to be at
+	// DeprecatedAt, which is zero if the header didn't say when.
+	Deprecated   bool
+	DeprecatedAt time.Time
+	// Sunset is when the resource is expected to stop responding, or zero
+	// if it isn't.
+	Sunset time.Time
+	// DeprecationLinks and SunsetLinks link to what documents the
+	// deprecation and the sunset, such as a migration guide.
+	DeprecationLinks Links
+	SunsetLinks      Links
+}
+
+// RetirementOf returns what resp says about the retirement of the resource
+// it is from. The links are those of resp about the resource itself, as
+// LinkSet.About finds them, their targets resolved as FromResponse does. A
+// Deprecation header that is a date, like "@1688169599", or that is "true",
+// or an HTTP date, as drafts of RFC 9745 had them, makes the resource
+// deprecated, and so does a rel=deprecation link. Headers that can't be
+// parsed are ignored.
+func RetirementOf(resp *http.Response) Retirement {
+	var r Retirement
+	if value := strings.TrimSpace(resp.Header.Get("Deprecation")); value != "" {
+		switch {
+		case strings.HasPrefix(value, "@"):
+			if seconds, err := strconv.ParseInt(value[1:], 10, 64); err == nil {
+				r.Deprecated, r.DeprecatedAt = true, time.Unix(seconds, 0).UTC()
+			}
+		case value == "true":
+			r.Deprecated = true
+		default:
+			if t, err := http.ParseTime(value); err == nil {
+				r.Deprecated, r.DeprecatedAt = true, t
+			}
+		}
+	}
+	if t, err := http.ParseTime(strings.TrimSpace(resp.Header.Get("Sunset"))); err == nil {
+		r.Sunset = t
+	}
+	set, _ := LinkSetFromResponse(resp)
+	links := set.Links
+	if set.Context != nil {
+		links = set.About(set.Context)
+	}
+	r.DeprecationLinks = links.Rel("deprecation")
+	r.SunsetLinks = links.Rel("sunset")
+	if len(r.DeprecationLinks) > 0 {
+		r.Deprecated = true
+	}
+	return r
+}
+
+// Retiring reports whether the resource is deprecated or has a sunset, so
+// that clients should move off it.
+func (r Retirement) Retiring() bool {
+	return r.Deprecated || !r.Sunset.IsZero()
+}
+
+// Notice returns the first link to what documents the retirement: a
+// rel=deprecation link, or failing that, a rel=sunset one. ok is false if
+// there is neither.
+func (r Retirement) Notice() (link Link, ok bool) {
+	for _, links := range []Links{r.DeprecationLinks, r.SunsetLinks} {
+		if len(links) > 0 {
+			return links[0], true
+		}
+	}
+	return Link{}, false
+}
@@ -0,0 +1,70 @@
+package webLinks_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/conslo/webLinks"
+)
+
+func response(url string, header http.Header) *http.Response {
+	req, _ := http.NewRequest("GET", url, nil)
+	return &http.Response{Header: header, Request: req}
+}
+
+func TestRetirementOf(t *testing.T) {
+	t.Parallel()
+	resp := response("https://api.example.com/v1/items", http.Header{
+		"Deprecation": {"@1688169599"},
+		"Sunset":      {"Sat, 31 Dec 2033 23:59:59 GMT"},
+		"Link": {`</docs/deprecation>; rel=deprecation; type="text/html", </docs/sunset>; rel="sunset", ` +
+			`</v1/other>; rel=sunset; anchor="/v1/other"`},
+	})
+	r := webLinks.RetirementOf(resp)
+	if !r.Deprecated || !r.DeprecatedAt.Equal(time.Unix(1688169599, 0)) || !r.Retiring() {
+		t.Fatalf("Got %+v\n", r)
+	}
+	if !r.Sunset.Equal(time.Date(2033, 12, 31, 23, 59, 59, 0, time.UTC)) {
+		t.Fatalf("Got sunset %v\n", r.Sunset)
+	}
+	if len(r.SunsetLinks) != 1 || r.SunsetLinks[0].URI != "https://api.example.com/docs/sunset" {
+		t.Fatalf("Expected only the sunset link about the resource, got %v\n", r.SunsetLinks)
+	}
+	if notice, ok := r.Notice(); !ok || notice.URI != "https://api.example.com/docs/deprecation" {
+		t.Fatalf("Got notice %v\n", notice)
+	}
+}
+
+var deprecationTests = []struct {
+	header     string
+	deprecated bool
+	at         time.Time
+}{
+	{"true", true, time.Time{}},
+	{"Sun, 11 Nov 2018 23:59:59 GMT", true, time.Date(2018, 11, 11, 23, 59, 59, 0, time.UTC)},
+	{"@x", false, time.Time{}},
+	{"soon", false, time.Time{}},
+	{"", false, time.Time{}},
+}
+
+func TestRetirementDeprecation(t *testing.T) {
+	t.Parallel()
+	for _, test := range deprecationTests {
+		r := webLinks.RetirementOf(response("https://api.example.com/", http.Header{"Deprecation": {test.header}}))
+		if r.Deprecated != test.deprecated || !r.DeprecatedAt.Equal(test.at) {
+			t.Fatalf("Got %+v for %q\n", r, test.header)
+		}
+	}
+	r := webLinks.RetirementOf(response("https://api.example.com/", http.Header{"Link": {`</notice>; rel=deprecation`}}))
+	if !r.Deprecated || !r.DeprecatedAt.IsZero() {
+		t.Fatalf("Expected a deprecation link to make it deprecated, got %+v\n", r)
+	}
+	r = webLinks.RetirementOf(response("https://api.example.com/", http.Header{}))
+	if r.Retiring() {
+		t.Fatalf("Got %+v\n", r)
+	}
+	if _, ok := r.Notice(); ok {
+		t.Fatalf("Expected no notice\n")
+	}
+}
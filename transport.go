@@ -0,0 +1,62 @@
+package webLinks
+
+import (
+	"context"
+	"net/http"
+)
+
+// A Transport is an http.RoundTripper that parses the "Link" header of every
+// response it gets, as FromResponse does, so that clients gain the links of
+// their responses without parsing them at each call site. The links are
+// passed to OnLinks, if it is set, and can be had from the response with
+// ResponseLinks:
+//
+//	client := &http.Client{Transport: &webLinks.Transport{}}
+//	resp, err := client.Get("https://api.example.com/items")
+//	...
+//	links, _ := webLinks.ResponseLinks(resp)
+type Transport struct {
+	// Base is the RoundTripper that sends the requests, or
+	// http.DefaultTransport if it is nil.
+	Base http.RoundTripper
+	// OnLinks, if set, is called with every response and its links, before
+	// the response is returned. It may be called concurrently.
+	OnLinks func(resp *http.Response, links Links)
+}
+
+type responseLinksKey struct{}
+
+// RoundTrip sends req with t.Base, and parses the links of the response. The
+// response's Request is replaced by a copy of req carrying them, as the
+// request itself mustn't be modified.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.Request == nil {
+		resp.Request = req
+	}
+	// Targets that can't be resolved are left as they were
+	links, _ := FromResponse(resp)
+	resp.Request = resp.Request.WithContext(context.WithValue(resp.Request.Context(), responseLinksKey{}, links))
+	if t.OnLinks != nil {
+		t.OnLinks(resp, links)
+	}
+	return resp, nil
+}
+
+// ResponseLinks returns the links a Transport found in resp, resolved as
+// FromResponse resolves them. ok is false if resp didn't come through a
+// Transport.
+func ResponseLinks(resp *http.Response) (links Links, ok bool) {
+	if resp.Request == nil {
+		return nil, false
+	}
+	links, ok = resp.Request.Context().Value(responseLinksKey{}).(Links)
+	return links, ok
+}
@@ -0,0 +1,57 @@
+package webLinks_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/conslo/webLinks"
+)
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", `</items?page=2>; rel=next`)
+	}))
+	defer srv.Close()
+	var mu sync.Mutex
+	var seen webLinks.Links
+	client := &http.Client{Transport: &webLinks.Transport{
+		Base: srv.Client().Transport,
+		OnLinks: func(resp *http.Response, links webLinks.Links) {
+			mu.Lock()
+			defer mu.Unlock()
+			seen = append(seen, links...)
+		},
+	}}
+	resp, err := client.Get(srv.URL + "/items")
+	if err != nil {
+		t.Fatalf("Unexpected error %v\n", err)
+	}
+	resp.Body.Close()
+	links, ok := webLinks.ResponseLinks(resp)
+	if !ok || len(links) != 1 || links[0].URI != srv.URL+"/items?page=2" {
+		t.Fatalf("Got %v, %v\n", links, ok)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !seen.Equal(links) {
+		t.Fatalf("Expected OnLinks to get %v, got %v\n", links, seen)
+	}
+	if resp.Request.URL.String() != srv.URL+"/items" {
+		t.Fatalf("Expected the request to be kept, got %v\n", resp.Request.URL)
+	}
+}
+
+func TestResponseLinksWithoutTransport(t *testing.T) {
+	t.Parallel()
+	resp := &http.Response{Header: http.Header{"Link": {`</a>; rel=next`}}}
+	if links, ok := webLinks.ResponseLinks(resp); ok || links != nil {
+		t.Fatalf("Got %v, %v\n", links, ok)
+	}
+	resp.Request = httptest.NewRequest("GET", "/", nil)
+	if _, ok := webLinks.ResponseLinks(resp); ok {
+		t.Fatalf("Expected no links from a response that didn't come through a Transport\n")
+	}
+}
@@ -1,139 +1,830 @@
 // Package webLinks provides a parser for web links.
 // More precisely this is the "Link" header, according to
-// http://tools.ietf.org/html/rfc5988
+// https://tools.ietf.org/html/rfc8288 (which obsoletes RFC 5988).
+//
+// Parsing follows the algorithm in Appendix B of RFC 8288.
 package webLinks
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
+	"unsafe"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
 )
 
 // Parse parses a "Link" header. This accepts only the value portion of
 // the header, not the whole header.
-func Parse(link string) Links {
-	// Strip whitespace
-	link = strings.Trim(link, " ")
+//
+// Parse never panics, whatever the input. Links too malformed to make sense
+// of are skipped, everything else is parsed on a best effort basis.
+//
+// opts configure how link is parsed, just as the fields of a Parser do; see
+// NewParser. Parse returns no error even when strict, so links rejected for
+// not conforming are simply left out.
+func Parse(link string, opts ...Option) Links {
+	links, _ := NewParser(opts...).Parse(link)
+	return links
+}
+
+// ParseStrict is like Parse, but rather than making a best effort it rejects
+// any link that does not conform to RFC 8288. The links that do conform are
+// returned along with an error that joins together a *SyntaxError for every
+// problem found. It is shorthand for a Parser with Strict set.
+func ParseStrict(link string) (Links, error) {
+	return (&Parser{Strict: true}).Parse(link)
+}
+
+// MustParse is like ParseStrict, but panics if link does not conform to
+// RFC 8288. It suits header values that are constants, like those of
+// package-level variables and tests.
+func MustParse(link string) Links {
+	links, err := ParseStrict(link)
+	if err != nil {
+		panic("webLinks: MustParse(" + strconv.Quote(link) + "): " + err.Error())
+	}
+	return links
+}
+
+// A Parser parses "Link" headers according to its configuration. The zero
+// value is ready to use, and behaves just like Parse.
+type Parser struct {
+	// Strict rejects input that does not conform to the grammar of RFC 8288,
+	// rather than guessing at what was meant: parameter names and unquoted
+	// values must be tokens, extended values must be well formed, and link
+	// targets must not contain whitespace or other characters that cannot
+	// appear in a URI-reference.
+	Strict bool
+
+	// MaxLength, MaxLinks and MaxParams limit how long a header may be in
+	// bytes, how many links it may hold and how many parameters each link
+	// may have, to guard against hostile input. Zero means no limit.
+	// Exceeding a limit fails the whole header with an error wrapping
+	// ErrLimitExceeded, strict or not.
+	MaxLength int
+	MaxLinks  int
+	MaxParams int
+
+	// UTF8Only rejects extended values in any charset other than UTF-8, as
+	// RFC 8187 requires of new headers. Strict implies UTF8Only. When not
+	// strict, such values are still decoded, but flagged with a warning.
+	UTF8Only bool
+
+	// BareTargets recognizes links whose target isn't enclosed in angle
+	// brackets, like "https://example.com/next; rel=next", as emitted by some
+	// sloppy servers. Such links are always reported as not conforming, so
+	// this has no effect when strict.
+	BareTargets bool
+
+	// NFC normalizes parameter values, once decoded, to Unicode
+	// Normalization Form C, so that they can be compared naively.
+	NFC bool
+
+	// KeepCase keeps parameter names spelt exactly as they were in the
+	// header. Names are case-insensitive, so by default they are lowercased,
+	// so that e.g. Params["rel"] finds "REL=next" too.
+	KeepCase bool
+
+	// RegisteredRels flags bare relation types, in rel and rev params, that
+	// aren't registered with IANA, as CheckRel does, with a warning, or an
+	// error when strict. Extension relation types, which are URIs, are
+	// always accepted.
+	RegisteredRels bool
+
+	// Lossless keeps the text of each link as it appeared in the header, so
+	// that String formats links that haven't been modified since exactly as
+	// they were, quoting, spacing, parameter order and all, and Links.String
+	// reproduces the header byte for byte. It suits proxies passing on links
+	// they don't touch. Links that have been modified are formatted afresh.
+	Lossless bool
+
+	// Base, if set, is what link targets and anchors are resolved against,
+	// as ResolveAll does.
+	Base *url.URL
+
+	// DefaultCharset is the charset assumed for values that don't state
+	// theirs: extended values without one, and plain values holding bytes
+	// that aren't US-ASCII, which RFC 7230 leaves as opaque obs-text and
+	// older servers send as ISO-8859-1. Such values are transcoded to UTF-8
+	// from it. By default they are left as they are.
+	DefaultCharset string
+}
+
+// Parse parses a "Link" header. Unless p is strict or has limits set, the
+// returned error is always nil.
+func (p *Parser) Parse(link string) (Links, error) {
+	links, _, err := p.parse(link)
+	return links, err
+}
 
-	thisLink := Link{}
-	uriEnd := strings.IndexRune(link, '>')
+// ParseBytes is like Parse, but parses a header held in b. The links
+// returned never refer to b's memory, so b may be reused as soon as
+// ParseBytes returns.
+func (p *Parser) ParseBytes(b []byte) (Links, error) {
+	links, _, err := p.run(parser{Parser: *p, s: bytesView(b)})
+	return links, err
+}
 
-	thisLink.URI = link[1:uriEnd]
+// ParseBytes is like Parse, but parses a header held in b, see
+// (*Parser).ParseBytes. The returned error is always nil.
+func ParseBytes(b []byte) (Links, error) {
+	return (&Parser{}).ParseBytes(b)
+}
 
-	paramsStart := strings.IndexRune(link[uriEnd:], ';') + uriEnd + 1
-	params, paramsEnd := parseLinkParams(link[paramsStart:])
-	paramsEnd += paramsStart
-	thisLink.Params = params
-	nextLink := strings.IndexRune(link[paramsEnd:], ',') + paramsEnd + 1
-	if nextLink == paramsEnd {
-		return Links{thisLink}
+// bytesView returns a string sharing b's memory, which must not be mutated
+// while the string is in use.
+func bytesView(b []byte) string {
+	if len(b) == 0 {
+		return ""
 	}
-	return append(Links{thisLink}, Parse(link[nextLink:])...)
+	return unsafe.String(&b[0], len(b))
 }
 
-func parseLinkParams(params string) (map[string]Param, int) {
-	paramsEnd := strings.IndexRune(params, ',')
-	if paramsEnd == -1 {
-		paramsEnd = len(params)
+func (p *Parser) parse(link string) (Links, []Warning, error) {
+	return p.run(parser{Parser: *p, s: link, owned: link})
+}
+
+func (p *Parser) run(ps parser) (Links, []Warning, error) {
+	if p.MaxLength > 0 && len(ps.s) > p.MaxLength {
+		return nil, nil, limitError("header is longer than %d bytes", p.MaxLength)
+	}
+	var links Links
+	var errs []error
+	// Where the last link ended, and the header they were all parsed from,
+	// for keeping them verbatim
+	var end int
+	var src *string
+	if p.Lossless {
+		src = new(string)
+		*src = ps.sub(0, len(ps.s))
+	}
+	for !ps.done() {
+		from := ps.pos
+		l, ok, err := ps.link()
+		if errors.Is(err, ErrLimitExceeded) {
+			return nil, nil, err
+		}
+		if err != nil {
+			// Give up on this link, but not on the ones after it
+			errs = append(errs, err)
+			ps.skipTo(",")
+			ps.pos++
+			continue
+		}
+		if ok {
+			if p.MaxLinks > 0 && len(links) == p.MaxLinks {
+				return nil, nil, limitError("header has more than %d links", p.MaxLinks)
+			}
+			l.Index = len(links)
+			if src != nil {
+				to := ps.pos
+				if to > len(*src) {
+					to = len(*src)
+				}
+				text := strings.TrimRight(strings.TrimSuffix((*src)[from:to], ","), " \t")
+				l.verbatim = &verbatim{src: src, sep: (*src)[end:from], text: text}
+				l.verbatim.formatted = l.formatted()
+				end = from + len(text)
+			}
+			links = append(links, l)
+		}
+	}
+	if src != nil && links != nil {
+		links[len(links)-1].verbatim.tail = (*src)[end:]
 	}
-	pStrs := strings.Split(params[:paramsEnd], ";")
-	mapped := make(map[string]Param, len(pStrs))
-	for _, p := range pStrs {
-		key, value := parseParam(p)
-		mapped[key] = value
+	if p.Base != nil {
+		links, _ = links.resolve(p.Base)
 	}
-	return mapped, paramsEnd
+	if links == nil && errs == nil {
+		if err := ps.fail(WarnNoLinks, 0, len(ps.s), "no links found"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return links, ps.warnings, errors.Join(errs...)
+}
+
+// ParseLenient is like Parse, but also returns a warning for every problem
+// it found, and worked around, in the header.
+func ParseLenient(link string) (Links, []Warning) {
+	return (&Parser{}).ParseLenient(link)
+}
+
+// ParseLenient is like the package level ParseLenient, but configured by p.
+// Strict is ignored, as problems are always reported as warnings, but
+// exceeding a limit still fails the whole header.
+func (p *Parser) ParseLenient(link string) (Links, []Warning) {
+	lenient := *p
+	lenient.Strict = false
+	links, warnings, _ := lenient.parse(link)
+	return links, warnings
 }
 
-func parseParam(param string) (string, Param) {
-	// Trim whitespace
-	param = strings.Trim(param, " ")
-	parts := strings.SplitN(param, "=", 2)
-	if len(parts) != 2 {
+// parser walks a header once, front to back, producing one link at a time.
+type parser struct {
+	Parser
+	s        string
+	pos      int
+	warnings []Warning
+
+	// unclosed is set once a target is found to have no closing '>', so
+	// that the targets after it don't each look to the end of s again.
+	unclosed bool
+
+	// owned holds the same bytes as s, and is what anything handed back to
+	// the caller is sliced out of. It is only different from s when s
+	// aliases a caller's []byte, in which case it is copied on first use.
+	owned string
+}
+
+// sub returns s[start:end], safe to hand back to the caller.
+func (p *parser) sub(start, end int) string {
+	if start == end {
+		return ""
+	}
+	if p.owned == "" {
+		p.owned = strings.Clone(p.s)
+	}
+	return p.owned[start:end]
+}
+
+// fail reports a problem with the header, found in s[start:end]. When strict
+// the problem is returned as a *SyntaxError and parsing should stop,
+// otherwise it is recorded as a warning and nil is returned, so that parsing
+// can carry on.
+func (p *parser) fail(kind WarningKind, start, end int, format string, args ...interface{}) error {
+	if p.Strict {
+		return &SyntaxError{
+			Err:    kindErrors[kind],
+			Msg:    fmt.Sprintf(format, args...),
+			Offset: start,
+			Text:   p.sub(start, end),
+		}
+	}
+	p.warn(kind, start, end, format, args...)
+	return nil
+}
+
+// warn records a warning about s[start:end], even when strict.
+func (p *parser) warn(kind WarningKind, start, end int, format string, args ...interface{}) {
+	p.warnings = append(p.warnings, Warning{
+		Kind:   kind,
+		Msg:    fmt.Sprintf(format, args...),
+		Offset: start,
+		Text:   p.sub(start, end),
+	})
+}
+
+func (p *parser) done() bool {
+	p.skipSpace()
+	return p.pos >= len(p.s)
+}
+
+// skipSpace skips optional whitespace (OWS and BWS in RFC 7230): spaces and
+// horizontal tabs.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// skipTo moves past any characters, quoted strings included, until it reaches
+// one of seps or the end of the header.
+func (p *parser) skipTo(seps string) {
+	if i := indexUnquoted(p.s[p.pos:], seps); i != -1 {
+		p.pos += i
+	} else {
+		p.pos = len(p.s)
+	}
+}
+
+// link parses a single link-value, consuming the ',' that follows it. ok is
+// false if the link was too malformed to be of use. If an error is returned
+// the ',' has not been consumed.
+func (p *parser) link() (l Link, ok bool, err error) {
+	start := p.pos
+	if p.s[p.pos] == ',' {
+		// Empty list element, which RFC 7230 says must be accepted and
+		// ignored, e.g. when several headers were joined carelessly
+		p.pos++
+		return l, false, nil
+	}
+
+	uri, ok, err := p.target()
+	if !ok || err != nil {
+		return l, false, err
+	}
+	l.URI = uri
+	l.Params = map[string]Param{}
+
+	end := p.pos
+	params := 0
+	// Names of the params seen so far that only count the first time
+	var seen []string
+	// RFC 2231 continuations by the name of the param they make up, in the
+	// order they first appeared
+	var bases []string
+	sections := map[string][]continuation{}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) || p.s[p.pos] == ',' {
+			break
+		}
+		if p.s[p.pos] != ';' {
+			// Junk, ignore it up to the next separator
+			junk := p.pos
+			p.skipTo(";,")
+			if err := p.fail(WarnUnexpected, junk, p.pos, "expected ';' or ','"); err != nil {
+				return l, false, err
+			}
+			continue
+		}
+		p.pos++
+
+		p.skipSpace()
+		if p.pos >= len(p.s) || p.s[p.pos] == ';' || p.s[p.pos] == ',' {
+			// Stray ';', nothing to see here
+			if err := p.fail(WarnEmptyParam, end, p.pos, "empty parameter"); err != nil {
+				return l, false, err
+			}
+			continue
+		}
+		paramStart := p.pos
+		name, value, err := p.param()
+		if err != nil {
+			return l, false, err
+		}
+		if params++; p.MaxParams > 0 && params > p.MaxParams {
+			return l, false, limitError("link has more than %d parameters", p.MaxParams)
+		}
+		end = p.pos
+
+		l.Ordered = append(l.Ordered, LinkParam{Name: name, Param: value})
+		if base, n, encoded, ok := section(name); ok {
+			// Put back together once the whole link has been read
+			if _, ok := sections[base]; !ok {
+				bases = append(bases, base)
+			}
+			sections[base] = append(sections[base], continuation{
+				n: n, encoded: encoded, Param: value, start: paramStart, end: p.pos,
+			})
+			continue
+		}
+		if err := p.add(&l, &seen, name, value, paramStart, p.pos); err != nil {
+			return l, false, err
+		}
+	}
+	for _, base := range bases {
+		name, value, err := p.reassemble(base, sections[base])
+		if err != nil {
+			return l, false, err
+		}
+		first := sections[base][0]
+		if err := p.add(&l, &seen, name, value, first.start, first.end); err != nil {
+			return l, false, err
+		}
+	}
+
+	if _, ok := l.Param("rel"); !ok {
+		if err := p.fail(WarnMissingRel, start, end, "link to %q has no rel parameter", l.URI); err != nil {
+			return l, false, err
+		}
+	}
+	// Consume the ','
+	p.pos++
+	return l, true, nil
+}
+
+// add stores the param called name in l, as found between start and end,
+// minding duplicates. seen holds the names of the params that only count the
+// first time which were already stored.
+func (p *parser) add(l *Link, seen *[]string, name string, value Param, start, end int) error {
+	if err := p.checkAttr(name, value, start, end); err != nil {
+		return err
+	}
+	key := strings.TrimSuffix(name, "*")
+	if prev, ok := l.Params[key]; ok {
+		if l.Duplicates == nil {
+			l.Duplicates = map[string][]Param{}
+		}
+		if _, ok := l.Duplicates[key]; !ok {
+			l.Duplicates[key] = []Param{prev}
+		}
+		l.Duplicates[key] = append(l.Duplicates[key], value)
+	}
+	if firstWins(name) {
+		lower := strings.ToLower(name)
+		if contains(*seen, lower) {
+			return p.fail(WarnDuplicateParam, start, end, "parameter %q appears more than once", name)
+		}
+		*seen = append(*seen, lower)
+	}
+	l.Params[key] = value
+	return nil
+}
+
+// indexUnquoted is like strings.IndexAny, but ignores any of chars found
+// inside a quoted string.
+func indexUnquoted(s string, chars string) int {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			// Skip whatever is escaped
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && strings.IndexByte(chars, s[i]) != -1:
+			return i
+		}
+	}
+	return -1
+}
+
+// target parses the link's target. ok is false if the link was too malformed
+// to be of use, in which case it has been skipped already unless an error is
+// returned.
+func (p *parser) target() (uri string, ok bool, err error) {
+	start := p.pos
+	if p.s[p.pos] != '<' {
+		if p.BareTargets {
+			return p.bareTarget()
+		}
+		// There's no telling where the target is, skip to the next link
+		p.skipTo(",")
+		if err := p.fail(WarnMissingBracket, start, p.pos, "link does not start with '<'"); err != nil {
+			return "", false, err
+		}
+		p.pos++
+		return "", false, nil
+	}
+	uriEnd := -1
+	if !p.unclosed {
+		uriEnd = strings.IndexByte(p.s[p.pos:], '>')
+		p.unclosed = uriEnd == -1
+	}
+	if uriEnd == -1 {
+		p.skipTo(",")
+		if err := p.fail(WarnMissingBracket, start, p.pos, "link has no closing '>'"); err != nil {
+			return "", false, err
+		}
+		p.pos++
+		return "", false, nil
+	}
+	uri = p.sub(p.pos+1, p.pos+uriEnd)
+	if !isURIReference(uri) {
+		if err := p.fail(WarnBadTarget, p.pos+1, p.pos+uriEnd, "link target %q is not a URI-reference", uri); err != nil {
+			return "", false, err
+		}
+	}
+	p.pos += uriEnd + 1
+	return uri, true, nil
+}
+
+// bareTarget parses a target that isn't enclosed in angle brackets, which
+// runs up to the first whitespace, ';' or ','.
+func (p *parser) bareTarget() (uri string, ok bool, err error) {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t;,", p.s[p.pos]) == -1 {
+		p.pos++
+	}
+	if p.pos == start {
+		// Not even a bare target, skip to the next link
+		p.skipTo(",")
+		if err := p.fail(WarnMissingBracket, start, p.pos, "link has no target"); err != nil {
+			return "", false, err
+		}
+		p.pos++
+		return "", false, nil
+	}
+	if err := p.fail(WarnMissingBracket, start, p.pos, "link target is not enclosed in '<' and '>'"); err != nil {
+		return "", false, err
+	}
+	return p.sub(start, p.pos), true, nil
+}
+
+// firstWins reports whether only the first occurrence of the param called
+// name counts, as RFC 8288 requires of rel, anchor, title, title*, media and
+// type. Any later occurrences are ignored.
+func firstWins(name string) bool {
+	switch strings.ToLower(name) {
+	case "rel", "anchor", "title", "title*", "media", "type":
+		return true
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// param parses a single link-param as described in RFC 8288, Appendix B.3,
+// returning its name as it should be stored along with its value.
+func (p *parser) param() (string, Param, error) {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(" \t=;,", p.s[p.pos]) == -1 {
+		p.pos++
+	}
+	key := p.sub(start, p.pos)
+	if !isToken(key) {
+		if err := p.fail(WarnBadParamName, start, p.pos, "parameter name %q is not a token", key); err != nil {
+			return "", Param{}, err
+		}
+	}
+	if !p.KeepCase {
+		key = strings.ToLower(key)
+	}
+	extended := strings.HasSuffix(key, "*")
+	_, n, _, isSection := section(key)
+
+	p.skipSpace()
+	if p.pos >= len(p.s) || p.s[p.pos] != '=' {
 		// This does not fall within the spec, so 'best effort'
-		return param, Param{}
+		return key, Param{Flag: true}, p.fail(WarnMissingValue, start, p.pos, "parameter %q has no value", key)
+	}
+	p.pos++
+	p.skipSpace()
+
+	var value string
+	valueStart, valueEnd := p.pos, p.pos
+	// Whether a problem with an extended value has already been reported
+	reported := false
+	if p.pos < len(p.s) && p.s[p.pos] == '"' {
+		var err error
+		value, err = p.quotedString()
+		if err != nil {
+			return "", Param{}, err
+		}
+		valueEnd = p.pos
+		if extended {
+			reported = true
+			if err := p.fail(WarnBadExtValue, valueStart, p.pos, "extended parameter %q must not be quoted", key); err != nil {
+				return "", Param{}, err
+			}
+		}
+	} else {
+		for p.pos < len(p.s) && p.s[p.pos] != ';' && p.s[p.pos] != ',' {
+			p.pos++
+		}
+		value = strings.TrimRight(p.sub(valueStart, p.pos), " \t")
+		valueEnd = valueStart + len(value)
+		var err error
+		switch {
+		case extended && isSection && n > 0 && !isPctEncoded(value),
+			extended && (!isSection || n == 0) && !isExtValue(value):
+			reported = true
+			err = p.fail(WarnBadExtValue, valueStart, valueEnd, "parameter %q has a malformed extended value", key)
+		case !extended && !isToken(value):
+			err = p.fail(WarnBadValue, valueStart, valueEnd, "unquoted value of parameter %q is not a token", key)
+		}
+		if err != nil {
+			return "", Param{}, err
+		}
 	}
-	key, value := parts[0], parts[1]
 
-	enc := "us-ascii"
-	lang := "en-us"
+	param := Param{
+		Value: value,
+		Enc:   "us-ascii",
+		Lang:  "en-us",
+		Raw:   p.sub(valueStart, valueEnd),
+	}
 
-	if strings.HasSuffix(key, "*") {
-		// value is URL encoded and *may* contain encoding+language meta
+	if isSection {
+		// Decoded once all of the param's sections have been put together
+		return key, param, nil
+	}
 
-		// Strip the * indicator
-		key = key[:len(key)-1]
+	if !extended && knownCharset(p.DefaultCharset) && !isASCII(value) {
+		if transcoded, err := toUTF8(p.DefaultCharset, value); err == nil {
+			param.Value, param.Enc = transcoded, p.DefaultCharset
+		}
+	}
+
+	if extended {
+		// value is percent encoded and *may* contain encoding+language meta
 
 		// Split out the encoding information
-		valueParts := strings.Split(value, "'")
+		valueParts := strings.SplitN(value, "'", 3)
 		if len(valueParts) == 3 {
-			enc = valueParts[0]
-			lang = valueParts[1]
+			param.Enc = valueParts[0]
+			param.Lang = valueParts[1]
 			value = valueParts[2]
+			if err := p.checkMeta(key, param.Enc, param.Lang, valueStart); err != nil {
+				return "", Param{}, err
+			}
 		}
 		// It's just encoded, leave the defaults
+		if len(valueParts) != 3 && p.DefaultCharset != "" {
+			param.Enc = p.DefaultCharset
+		}
 
 		// Decode this sucker
-		decoded, err := url.QueryUnescape(value)
-		if err == nil {
-			value = decoded
+		decoded, ok := pctDecode(value)
+		param.Value = decoded
+		param.undecoded = !ok || !knownCharset(param.Enc)
+		if !ok && !reported {
+			if err := p.fail(WarnBadExtValue, valueStart, p.pos, "parameter %q is not properly encoded", key); err != nil {
+				return "", Param{}, err
+			}
 		}
 		// not within spec, just leave it encoded
-	} else {
-		// It's not encoded, but it's quoted
-		// Let's dequote it
-		var dequoted string
-		n, err := fmt.Sscanf(value, "%q", &dequoted)
-		if n == 1 && err == nil {
-			value = dequoted
+
+		// Make sure we hand back UTF-8, whatever it was encoded with
+		if ok {
+			if transcoded, err := toUTF8(param.Enc, decoded); err == nil {
+				param.Value = transcoded
+			} else {
+				param.undecoded = true
+				if err := p.fail(WarnBadExtValue, valueStart, p.pos, "parameter %q is not valid %s", key, param.Enc); err != nil {
+					return "", Param{}, err
+				}
+			}
 		}
-		// ???, just leave it as is
 	}
-	p := Param{
-		Value: value,
-		Enc:   enc,
-		Lang:  lang,
+	if p.NFC {
+		param.Value = norm.NFC.String(param.Value)
 	}
-	return key, p
+	return key, param, nil
+}
+
+// checkMeta reports any problem with enc and lang, the charset and language
+// of the extended param called key whose value starts at start.
+func (p *parser) checkMeta(key, enc, lang string, start int) error {
+	if (p.Strict || p.UTF8Only) && !strings.EqualFold(enc, "UTF-8") {
+		if err := p.fail(WarnNotUTF8, start, start+len(enc), "parameter %q uses charset %q rather than UTF-8", key, enc); err != nil {
+			return err
+		}
+	}
+	if !knownCharset(enc) {
+		p.warn(WarnUnknownCharset, start, start+len(enc), "parameter %q uses unknown charset %q", key, enc)
+	}
+	if lang != "" {
+		if _, err := language.Parse(lang); err != nil {
+			langStart := start + len(enc) + 1
+			return p.fail(WarnBadLanguage, langStart, langStart+len(lang), "parameter %q has malformed language tag %q", key, lang)
+		}
+	}
+	return nil
+}
+
+// quotedString parses the quoted-string starting at the current position, as
+// described in RFC 8288, Appendix B.4.
+func (p *parser) quotedString() (string, error) {
+	// Discard the opening quote
+	quote := p.pos
+	p.pos++
+	start := p.pos
+
+	// Most quoted strings don't escape anything, so avoid copying them
+	for p.pos < len(p.s) && p.s[p.pos] != '"' && p.s[p.pos] != '\\' {
+		p.pos++
+	}
+	if p.pos < len(p.s) && p.s[p.pos] == '"' {
+		p.pos++
+		return p.sub(start, p.pos-1), nil
+	}
+
+	out := []byte(p.s[start:p.pos])
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return string(out), nil
+		case '\\':
+			if p.pos >= len(p.s) {
+				continue
+			}
+			c = p.s[p.pos]
+			p.pos++
+		}
+		out = append(out, c)
+	}
+	return string(out), p.fail(WarnUnterminatedQuote, quote, p.pos, "unterminated quoted string")
 }
 
 // Link represents a link from a parsed Link header
 type Link struct {
-	URI    string
+	URI string
+	// Params holds the link's parameters by name. Names are lowercased unless
+	// the link was parsed with a Parser that keeps their case.
 	Params map[string]Param
+	// Duplicates holds, by name and in order, every occurrence of the
+	// parameters that appeared more than once. Params only holds one of them:
+	// the first for rel, anchor, title, title*, media and type, as RFC 8288
+	// requires later ones to be ignored, and the last for any other.
+	Duplicates map[string][]Param
+	// Ordered holds every parameter in the order they appeared, duplicates
+	// included. Extended parameters keep the "*" suffix on their name.
+	Ordered []LinkParam
+	// Index is the position of the link among those it was parsed along
+	// with, counting from 0.
+	Index int
+
+	// verbatim is the link as it appeared in the header, when parsed
+	// losslessly
+	verbatim *verbatim
+}
+
+// LinkParam is a parameter, along with its name, as it appeared in a link.
+type LinkParam struct {
+	Name string
+	Param
+}
+
+// Values returns every occurrence of the parameter called name, in the order
+// they appeared.
+func (l Link) Values(name string) []Param {
+	if params, ok := l.Duplicates[name]; ok {
+		return params
+	}
+	if param, ok := l.Params[name]; ok {
+		return []Param{param}
+	}
+	return nil
+}
+
+// Param returns the parameter called name, ignoring case. ok is false if the
+// link has no such parameter, as opposed to one with an empty value. As in
+// Params, an extended parameter is found under its name without the "*", so
+// Param("title") returns either the title or the title*; see Title to choose
+// between them.
+func (l Link) Param(name string) (param Param, ok bool) {
+	if p, ok := l.Params[name]; ok {
+		return p, true
+	}
+	for key, p := range l.Params {
+		if strings.EqualFold(key, name) {
+			return p, true
+		}
+	}
+	return Param{}, false
 }
 
 // Links represents a group of links. This allows useful parsing on top of
 // groups of links.
 type Links []Link
 
-// Map returns links mapped in relation:link format. Duplicates have
-// undefined behavior, links without a "rel" param are omitted.
+// Map returns links mapped in relation:link format. A "rel" param may hold
+// several space separated relation types, in which case the link is mapped
+// under each of them. Duplicates have undefined behavior, links without a
+// "rel" param are omitted.
 // Links with a "rel" param, but alternative encoding, are stored according to
 // UTF-8 encoding.
 func (l Links) Map() map[string]Link {
 	these := make(map[string]Link, len(l))
 
 	for _, link := range l {
-		if rel, ok := link.Params["rel"]; ok {
-			these[rel.Value] = link
+		for _, rel := range link.relTypes() {
+			these[rel] = link
 		}
 	}
 
 	return these
 }
 
+// relTypes returns each of the relation types in the link's "rel" param.
+func (l Link) relTypes() []string {
+	rel, ok := l.Param("rel")
+	if !ok {
+		return nil
+	}
+	return strings.Fields(rel.Value)
+}
+
 // Param represents a single link parameter. This is necessary because
 // parameters can state their own encoding.
-// See http://tools.ietf.org/html/rfc2231
+// See http://tools.ietf.org/html/rfc8187
 //
-// Although the encoding may not be UTF-8 compliant, we still return a UTF-8
-// string. Other encodings must be handled by the caller if desired.
+// Values in any charset known to golang.org/x/text/encoding/ianaindex are
+// transcoded, so that Value always holds UTF-8. Values in other charsets are
+// left as they were, once percent decoded, and must be handled by the caller
+// if desired.
 //
-// Multipart params are not supported, but may be reconscruted on their own
-// by contatenating all the param*N named params in order of N.
+// Params split into RFC 2231 continuations, like title*0 and title*1*, are
+// put back together and stored under their plain name, while Ordered keeps
+// each section as it appeared, undecoded.
 type Param struct {
 	Value string
 	Enc   string
 	Lang  string
+	// Raw is the value exactly as it appeared in the header, before any
+	// decoding: quotes, escapes, charset and language included.
+	Raw string
+	// Flag is set for parameters that appeared without any value at all,
+	// like "; foo", as opposed to with an empty one, like "; foo=""". This
+	// does not fall within the spec, but is seen in the wild.
+	Flag bool
+
+	// undecoded is set for extended values that could not be decoded to
+	// UTF-8, whose Value is then left as it was.
+	undecoded bool
 }
@@ -1,7 +1,10 @@
 package webLinks_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/conslo/webLinks"
 )
@@ -14,10 +17,10 @@ var tests = []struct {
 		`<http://example.com/TheBook/chapter2>; rel="previous"; title="previous chapter"`,
 		[]webLinks.Link{
 			{
-				"http://example.com/TheBook/chapter2",
-				map[string]webLinks.Param{
-					"rel":   {Value: "previous", Enc: "us-ascii", Lang: "en-us"},
-					"title": {Value: "previous chapter", Enc: "us-ascii", Lang: "en-us"},
+				URI: "http://example.com/TheBook/chapter2",
+				Params: map[string]webLinks.Param{
+					"rel":   {Value: "previous", Enc: "us-ascii", Lang: "en-us", Raw: `"previous"`},
+					"title": {Value: "previous chapter", Enc: "us-ascii", Lang: "en-us", Raw: `"previous chapter"`},
 				},
 			},
 		},
@@ -26,9 +29,9 @@ var tests = []struct {
 		`</>; rel="http://example.net/foo"`,
 		[]webLinks.Link{
 			{
-				"/",
-				map[string]webLinks.Param{
-					"rel": {Value: "http://example.net/foo", Enc: "us-ascii", Lang: "en-us"},
+				URI: "/",
+				Params: map[string]webLinks.Param{
+					"rel": {Value: "http://example.net/foo", Enc: "us-ascii", Lang: "en-us", Raw: `"http://example.net/foo"`},
 				},
 			},
 		},
@@ -37,17 +40,101 @@ var tests = []struct {
 		`</TheBook/chapter2>; rel="previous"; title*=UTF-8'de'letztes%20Kapitel, </TheBook/chapter4>; rel="next"; title*=UTF-8'de'n%c3%a4chstes%20Kapitel`,
 		[]webLinks.Link{
 			{
-				"/TheBook/chapter2",
-				map[string]webLinks.Param{
-					"rel":   {Value: "previous", Enc: "us-ascii", Lang: "en-us"},
-					"title": {Value: "letztes Kapitel", Enc: "UTF-8", Lang: "de"},
+				URI: "/TheBook/chapter2",
+				Params: map[string]webLinks.Param{
+					"rel":   {Value: "previous", Enc: "us-ascii", Lang: "en-us", Raw: `"previous"`},
+					"title": {Value: "letztes Kapitel", Enc: "UTF-8", Lang: "de", Raw: `UTF-8'de'letztes%20Kapitel`},
 				},
 			},
 			{
-				"/TheBook/chapter4",
-				map[string]webLinks.Param{
-					"rel":   {Value: "next", Enc: "us-ascii", Lang: "en-us"},
-					"title": {Value: "nächstes Kapitel", Enc: "UTF-8", Lang: "de"},
+				URI: "/TheBook/chapter4",
+				Params: map[string]webLinks.Param{
+					"rel":   {Value: "next", Enc: "us-ascii", Lang: "en-us", Raw: `"next"`},
+					"title": {Value: "nächstes Kapitel", Enc: "UTF-8", Lang: "de", Raw: `UTF-8'de'n%c3%a4chstes%20Kapitel`},
+				},
+			},
+		},
+	},
+	{
+		`<https://example.com/2>; rel="next"; title="a, b", <https://example.com/9>; rel="last"`,
+		[]webLinks.Link{
+			{
+				URI: "https://example.com/2",
+				Params: map[string]webLinks.Param{
+					"rel":   {Value: "next", Enc: "us-ascii", Lang: "en-us", Raw: `"next"`},
+					"title": {Value: "a, b", Enc: "us-ascii", Lang: "en-us", Raw: `"a, b"`},
+				},
+			},
+			{
+				URI: "https://example.com/9",
+				Params: map[string]webLinks.Param{
+					"rel": {Value: "last", Enc: "us-ascii", Lang: "en-us", Raw: `"last"`},
+				},
+			},
+		},
+	},
+	{
+		`</a>; title="foo;bar"; rel="next", </b>; title="x;y, z"; rel="last"`,
+		[]webLinks.Link{
+			{
+				URI: "/a",
+				Params: map[string]webLinks.Param{
+					"rel":   {Value: "next", Enc: "us-ascii", Lang: "en-us", Raw: `"next"`},
+					"title": {Value: "foo;bar", Enc: "us-ascii", Lang: "en-us", Raw: `"foo;bar"`},
+				},
+			},
+			{
+				URI: "/b",
+				Params: map[string]webLinks.Param{
+					"rel":   {Value: "last", Enc: "us-ascii", Lang: "en-us", Raw: `"last"`},
+					"title": {Value: "x;y, z", Enc: "us-ascii", Lang: "en-us", Raw: `"x;y, z"`},
+				},
+			},
+		},
+	},
+	{
+		`</terms>; rel="copyright"; anchor="#foo"`,
+		[]webLinks.Link{
+			{
+				URI: "/terms",
+				Params: map[string]webLinks.Param{
+					"rel":    {Value: "copyright", Enc: "us-ascii", Lang: "en-us", Raw: `"copyright"`},
+					"anchor": {Value: "#foo", Enc: "us-ascii", Lang: "en-us", Raw: `"#foo"`},
+				},
+			},
+		},
+	},
+	{
+		`<http://example.org/>; rel="start http://example.net/relation/other"`,
+		[]webLinks.Link{
+			{
+				URI: "http://example.org/",
+				Params: map[string]webLinks.Param{
+					"rel": {Value: "start http://example.net/relation/other", Enc: "us-ascii", Lang: "en-us", Raw: `"start http://example.net/relation/other"`},
+				},
+			},
+		},
+	},
+	{
+		`</a>; rel = next ; title="say \"hi\" \\o/"`,
+		[]webLinks.Link{
+			{
+				URI: "/a",
+				Params: map[string]webLinks.Param{
+					"rel":   {Value: "next", Enc: "us-ascii", Lang: "en-us", Raw: `next`},
+					"title": {Value: `say "hi" \o/`, Enc: "us-ascii", Lang: "en-us", Raw: `"say \"hi\" \\o/"`},
+				},
+			},
+		},
+	},
+	{
+		`</a>; REL="next"; Title*=UTF-8'en'Next%20page`,
+		[]webLinks.Link{
+			{
+				URI: "/a",
+				Params: map[string]webLinks.Param{
+					"rel":   {Value: "next", Enc: "us-ascii", Lang: "en-us", Raw: `"next"`},
+					"title": {Value: "Next page", Enc: "UTF-8", Lang: "en", Raw: `UTF-8'en'Next%20page`},
 				},
 			},
 		},
@@ -82,7 +169,7 @@ func TestParseLinksParamValue(t *testing.T) {
 			}
 			for k, v := range test.links[i].Params {
 				if link.Params[k] != v {
-					t.Fatalf("Value mismatch, got %q expected %q\n", link.Params[k], v)
+					t.Fatalf("Value mismatch, got %+v expected %+v\n", link.Params[k], v)
 				}
 			}
 		}
@@ -96,7 +183,7 @@ func TestParseLinksIntoMap(t *testing.T) {
 			URI: "some uri",
 			Params: map[string]webLinks.Param{
 				"rel": {
-					Value: "some relation",
+					Value: "some-relation",
 					Enc:   "doesn't matter",
 					Lang:  "this either",
 				},
@@ -106,7 +193,7 @@ func TestParseLinksIntoMap(t *testing.T) {
 			URI: "another uri",
 			Params: map[string]webLinks.Param{
 				"rel": {
-					Value: "another relation",
+					Value: "another-relation",
 				},
 			},
 		},
@@ -114,12 +201,160 @@ func TestParseLinksIntoMap(t *testing.T) {
 
 	these := links.Map()
 
-	if these["some relation"].URI != "some uri" {
-		t.Fatalf("Got bad relation in map. Got %q expected %1\n", these["some relation"].URI, "some uri")
+	if these["some-relation"].URI != "some uri" {
+		t.Fatalf("Got bad relation in map. Got %q expected %q\n", these["some-relation"].URI, "some uri")
+	}
+
+	if these["another-relation"].URI != "another uri" {
+		t.Fatalf("Got bad relation in map. Got %q expected %q\n", these["another-relation"].URI, "another uri")
+	}
+}
+
+func TestParseLinksIntoMapMultipleRels(t *testing.T) {
+	t.Parallel()
+	these := webLinks.Parse(`</9>; rel="next  last", <http://example.org/>; rel="start http://example.net/relation/other"`).Map()
+
+	expected := map[string]string{
+		"next":                              "/9",
+		"last":                              "/9",
+		"start":                             "http://example.org/",
+		"http://example.net/relation/other": "http://example.org/",
+	}
+	if len(these) != len(expected) {
+		t.Fatalf("Length mismatch, got %d expected %d\n", len(these), len(expected))
+	}
+	for rel, uri := range expected {
+		if these[rel].URI != uri {
+			t.Fatalf("Got bad relation in map. Got %q expected %q\n", these[rel].URI, uri)
+		}
+	}
+}
+
+func TestParseStrictValid(t *testing.T) {
+	t.Parallel()
+	for _, test := range tests {
+		links, err := webLinks.ParseStrict(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s\n", test.input, err)
+		}
+		if len(links) != len(test.links) {
+			t.Fatalf("Length mismatch, got %d expected %d\n", len(links), len(test.links))
+		}
 	}
+}
 
-	if these["another relation"].URI != "another uri" {
-		t.Fatalf("Got bad relation in map. Got %q expected %1\n", these["another relation"].URI, "another uri")
+var malformed = []string{
+	``,
+	`foo; rel=next`,
+	`<http://example.com/; rel=next`,
+	`<http://example.com/> rel=next`,
+	`<http://example.com/>; rel`,
+	`<http://example.com/>; rel="next`,
+	`<http://example.com/>; title*=UTF-8'de'%zz`,
+	`</a b>; rel=next`,
+	`</a>; r@l=next`,
+	`</a>; rel=next page`,
+	`</a>; rel=next,page`,
+	`</a>; title*="UTF-8'de'Kapitel"`,
+	`</a>; title*=UTF-8'de`,
+	`</a>; title*=UTF-8'de'Kapitel 2`,
+}
+
+func TestParserLenientNoError(t *testing.T) {
+	t.Parallel()
+	p := webLinks.Parser{}
+	for _, input := range malformed {
+		if _, err := p.Parse(input); err != nil {
+			t.Fatalf("Unexpected error for %q: %s\n", input, err)
+		}
+	}
+}
+
+func TestParseStrictMalformed(t *testing.T) {
+	t.Parallel()
+	for _, input := range malformed {
+		if _, err := webLinks.ParseStrict(input); err == nil {
+			t.Fatalf("Expected an error for %q\n", input)
+		}
+	}
+}
+
+func TestParseNeverPanics(t *testing.T) {
+	t.Parallel()
+	inputs := append([]string{}, malformed...)
+	for _, test := range tests {
+		inputs = append(inputs, test.input)
+	}
+	for _, input := range inputs {
+		// Every truncation of a header is a good source of broken headers
+		for i := 0; i <= len(input); i++ {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("Parse panicked on %q: %v\n", input[:i], r)
+					}
+				}()
+				webLinks.Parse(input[:i])
+			}()
+		}
+	}
+}
+
+var skipped = []struct {
+	input string
+	uris  []string
+}{
+	{``, nil},
+	{`foo; rel=next`, nil},
+	{`foo; rel=next, </next>; rel=next`, []string{"/next"}},
+	{`<http://example.com/; rel=next`, nil},
+	{`</a> junk; rel=next, </b>`, []string{"/a", "/b"}},
+	{`</a>, </b>; rel=next`, []string{"/a", "/b"}},
+}
+
+func TestParseSkipsMalformed(t *testing.T) {
+	t.Parallel()
+	for _, test := range skipped {
+		links := webLinks.Parse(test.input)
+		if len(links) != len(test.uris) {
+			t.Fatalf("Length mismatch for %q, got %d expected %d\n", test.input, len(links), len(test.uris))
+		}
+		for i, link := range links {
+			if link.URI != test.uris[i] {
+				t.Fatalf("Got the wrong URI, got %q expected %q\n", link.URI, test.uris[i])
+			}
+		}
+	}
+}
+
+func TestParseManyLinks(t *testing.T) {
+	t.Parallel()
+	const n = 50000
+	links := webLinks.Parse(strings.Repeat(`</page>; rel="item", `, n))
+	if len(links) != n {
+		t.Fatalf("Length mismatch, got %d expected %d\n", len(links), n)
+	}
+}
+
+// TestParseUnclosedTargetsLinear doesn't run in parallel, as it times
+// parsing a header a quarter the size of another, for which the time taken
+// should grow in proportion; it would be 16 times longer were it quadratic.
+func TestParseUnclosedTargetsLinear(t *testing.T) {
+	parse := func(n int) time.Duration {
+		header := strings.Repeat("<a, ", n)
+		best := time.Duration(-1)
+		for i := 0; i < 3; i++ {
+			start := time.Now()
+			webLinks.Parse(header)
+			if took := time.Since(start); best < 0 || took < best {
+				best = took
+			}
+		}
+		return best
+	}
+	small, large := parse(1<<16), parse(1<<18)
+	if large > 8*small+10*time.Millisecond {
+		t.Fatalf("1MB of unclosed targets took %v, a quarter of it %v\n", large, small)
 	}
 }
 
@@ -155,3 +390,281 @@ func BenchmarkParseLinksSimplist(b *testing.B) {
 		webLinks.Parse(this)
 	}
 }
+
+func BenchmarkParseLinksMany(b *testing.B) {
+	this := strings.Repeat(`</TheBook/chapter2>; rel="previous"; title="previous chapter", `, 1000)
+	b.SetBytes(int64(len([]byte(this))))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		webLinks.Parse(this)
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	t.Parallel()
+	for _, test := range tests {
+		b := []byte(test.input)
+		links, err := webLinks.ParseBytes(b)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s\n", test.input, err)
+		}
+		// The links must not change along with the buffer
+		for i := range b {
+			b[i] = 'x'
+		}
+		if len(links) != len(test.links) {
+			t.Fatalf("Length mismatch, got %d expected %d\n", len(links), len(test.links))
+		}
+		for i, link := range links {
+			if link.URI != test.links[i].URI {
+				t.Fatalf("Got the wrong URI, got %q expected %q\n", link.URI, test.links[i].URI)
+			}
+			for k, v := range test.links[i].Params {
+				if link.Params[k] != v {
+					t.Fatalf("Value mismatch, got %+v expected %+v\n", link.Params[k], v)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkParseBytesSimplist(b *testing.B) {
+	this := []byte(`<http://example.com/>; rel="previous"`)
+	b.SetBytes(int64(len(this)))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		webLinks.ParseBytes(this)
+	}
+}
+
+var limitTests = []struct {
+	parser webLinks.Parser
+	input  string
+	ok     bool
+}{
+	{webLinks.Parser{MaxLength: 16}, `</a>; rel="next"`, true},
+	{webLinks.Parser{MaxLength: 15}, `</a>; rel="next"`, false},
+	{webLinks.Parser{MaxLinks: 2}, `</a>; rel=next, </b>; rel=prev`, true},
+	{webLinks.Parser{MaxLinks: 2}, `</a>; rel=next, </b>; rel=prev, </c>; rel=last`, false},
+	{webLinks.Parser{MaxParams: 2}, `</a>; rel=next; title=a`, true},
+	{webLinks.Parser{MaxParams: 2}, `</a>; rel=next, </b>; rel=prev; title=b; type=c`, false},
+}
+
+func TestParserLimits(t *testing.T) {
+	t.Parallel()
+	for _, test := range limitTests {
+		links, err := test.parser.Parse(test.input)
+		if test.ok && (err != nil || links == nil) {
+			t.Fatalf("Unexpected error for %q: %v\n", test.input, err)
+		}
+		if !test.ok && (!errors.Is(err, webLinks.ErrLimitExceeded) || links != nil) {
+			t.Fatalf("Expected ErrLimitExceeded for %q, got %v\n", test.input, err)
+		}
+	}
+}
+
+func TestParserKeepCase(t *testing.T) {
+	t.Parallel()
+	p := webLinks.Parser{KeepCase: true, Strict: true}
+	links, err := p.Parse(`</a>; REL="next"; Title*=UTF-8'en'Next%20page`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err)
+	}
+	if links[0].Params["REL"].Value != "next" || links[0].Params["Title"].Value != "Next page" {
+		t.Fatalf("Expected the original spelling, got %v\n", links[0].Params)
+	}
+}
+
+func TestParseFirstOccurrenceWins(t *testing.T) {
+	t.Parallel()
+	links, warnings := webLinks.ParseLenient(`</a>; rel=next; title=one; hreflang=en; rel=prev; title=two; title=three; hreflang=de`)
+	link := links[0]
+	if link.Params["rel"].Value != "next" || link.Params["title"].Value != "one" {
+		t.Fatalf("Expected the first occurrences, got %v\n", link.Params)
+	}
+	if link.Params["hreflang"].Value != "de" {
+		t.Fatalf("Expected the last hreflang, got %v\n", link.Params["hreflang"])
+	}
+	if rels := link.Values("rel"); len(rels) != 2 || rels[1].Value != "prev" {
+		t.Fatalf("Expected the ignored rel, got %v\n", rels)
+	}
+	if len(warnings) != 3 || warnings[0].Kind != webLinks.WarnDuplicateParam {
+		t.Fatalf("Expected a warning per duplicate, got %v\n", warnings)
+	}
+
+	if _, err := webLinks.ParseStrict(`</a>; rel=next; rel=prev`); !errors.Is(err, webLinks.ErrDuplicateParam) {
+		t.Fatalf("Expected ErrDuplicateParam, got %v\n", err)
+	}
+}
+
+func TestLinkValues(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; rel=alternate; hreflang=en; title=one; hreflang=de; title*=UTF-8''two; hreflang=fr`)[0]
+
+	expected := map[string][]string{
+		"rel":      {"alternate"},
+		"hreflang": {"en", "de", "fr"},
+		"title":    {"one", "two"},
+		"media":    nil,
+	}
+	for name, values := range expected {
+		got := link.Values(name)
+		if len(got) != len(values) {
+			t.Fatalf("Length mismatch for %q, got %d expected %d\n", name, len(got), len(values))
+		}
+		for i, param := range got {
+			if param.Value != values[i] {
+				t.Fatalf("Value mismatch for %q, got %q expected %q\n", name, param.Value, values[i])
+			}
+		}
+	}
+}
+
+func TestLinkOrdered(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; title=one; rel=next; hreflang=en; title*=UTF-8''two; rel=ignored; flag`)[0]
+
+	expected := []struct{ name, value string }{
+		{"title", "one"},
+		{"rel", "next"},
+		{"hreflang", "en"},
+		{"title*", "two"},
+		{"rel", "ignored"},
+		{"flag", ""},
+	}
+	if len(link.Ordered) != len(expected) {
+		t.Fatalf("Length mismatch, got %d expected %d\n", len(link.Ordered), len(expected))
+	}
+	for i, param := range link.Ordered {
+		if param.Name != expected[i].name || param.Value != expected[i].value {
+			t.Fatalf("Param mismatch, got %s=%q expected %s=%q\n", param.Name, param.Value, expected[i].name, expected[i].value)
+		}
+	}
+}
+
+func TestParseFlagParams(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; rel=preload; nopush; empty=""; blank=`)[0]
+	if param, ok := link.Params["nopush"]; !ok || !param.Flag || param.Value != "" {
+		t.Fatalf("Expected a flag, got %v\n", param)
+	}
+	for _, name := range []string{"empty", "blank", "rel"} {
+		if param, ok := link.Params[name]; !ok || param.Flag {
+			t.Fatalf("Expected %q to have a value, got %v\n", name, param)
+		}
+	}
+}
+
+func TestParseWhitespace(t *testing.T) {
+	t.Parallel()
+	inputs := []string{
+		"</a>;rel=next;title=\"a b\",</b>;rel=prev",
+		"\t</a>\t;\trel\t=\tnext\t;\ttitle\t=\t\"a b\"\t,\t</b> ; rel = prev\t",
+		"  </a>  ;  rel  =  next  ;  title  =  \"a b\"  ,  </b>  ;  rel  =  prev  ",
+	}
+	for _, input := range inputs {
+		links, err := webLinks.ParseStrict(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s\n", input, err)
+		}
+		if len(links) != 2 {
+			t.Fatalf("Length mismatch for %q, got %d expected %d\n", input, len(links), 2)
+		}
+		a, b := links[0].Params, links[1].Params
+		if a["rel"].Value != "next" || a["title"].Value != "a b" || b["rel"].Value != "prev" {
+			t.Fatalf("Got unexpected params for %q: %v %v\n", input, a, b)
+		}
+	}
+}
+
+func TestParseEmptyElements(t *testing.T) {
+	t.Parallel()
+	inputs := []string{
+		`, </a>; rel=next, , </b>; rel=prev,`,
+		`,,</a>; rel=next,,,</b>; rel=prev,,`,
+		", \t, </a>; rel=next , ,\t</b>; rel=prev , ",
+	}
+	for _, input := range inputs {
+		links, warnings := webLinks.ParseLenient(input)
+		if len(links) != 2 || len(warnings) != 0 {
+			t.Fatalf("Expected 2 links and no warnings for %q, got %v %v\n", input, links, warnings)
+		}
+		if _, err := webLinks.ParseStrict(input); err != nil {
+			t.Fatalf("Unexpected error for %q: %s\n", input, err)
+		}
+	}
+}
+
+func TestParserBareTargets(t *testing.T) {
+	t.Parallel()
+	const input = `https://example.com/next; rel=next, </prev>; rel=prev, ; rel=none`
+	p := webLinks.Parser{BareTargets: true}
+
+	links, warnings := p.ParseLenient(input)
+	if len(links) != 2 || links[0].URI != "https://example.com/next" || links[0].Params["rel"].Value != "next" || links[1].URI != "/prev" {
+		t.Fatalf("Got unexpected links %v\n", links)
+	}
+	if len(warnings) != 2 || warnings[0].Kind != webLinks.WarnMissingBracket || warnings[0].Text != "https://example.com/next" {
+		t.Fatalf("Expected the bare target to be flagged, got %v\n", warnings)
+	}
+
+	if links := webLinks.Parse(input); len(links) != 1 {
+		t.Fatalf("Expected bare targets to be skipped by default, got %v\n", links)
+	}
+
+	p.Strict = true
+	if _, err := p.Parse(input); !errors.Is(err, webLinks.ErrMissingAngleBracket) {
+		t.Fatalf("Expected ErrMissingAngleBracket, got %v\n", err)
+	}
+}
+
+func TestLinkParam(t *testing.T) {
+	t.Parallel()
+	link := webLinks.Parse(`</a>; rel=next; empty=""; flag`)[0]
+	for _, test := range []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{"rel", "next", true},
+		{"REL", "next", true},
+		{"empty", "", true},
+		{"flag", "", true},
+		{"missing", "", false},
+	} {
+		param, ok := link.Param(test.name)
+		if ok != test.ok || param.Value != test.value {
+			t.Fatalf("Got %+v %v for %q expected %q %v\n", param, ok, test.name, test.value, test.ok)
+		}
+	}
+
+	kept, _ := (&webLinks.Parser{KeepCase: true}).Parse(`</a>; Rel=next; Title="x"`)
+	if param, ok := kept[0].Param("title"); !ok || param.Value != "x" {
+		t.Fatalf("Got %+v %v\n", param, ok)
+	}
+	if !kept[0].HasRel("next") {
+		t.Fatalf("Expected the rel to be found whatever its case\n")
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	t.Parallel()
+	links := webLinks.MustParse(`</a>; rel=next, </b>; rel=prev`)
+	if len(links) != 2 || links[1].URI != "/b" {
+		t.Fatalf("Got %s\n", links)
+	}
+	for _, input := range []string{``, `</a>; rel=next, </b`, `</a>; title="x"`} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Expected a panic for %q\n", input)
+				}
+			}()
+			webLinks.MustParse(input)
+		}()
+	}
+}
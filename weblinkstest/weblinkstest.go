@@ -0,0 +1,96 @@
+// Package weblinkstest provides assertions about Link headers, and a recorder
+// that captures the ones an http.Handler emits, for use in tests:
+//
+//	rec := weblinkstest.Record(handler, httptest.NewRequest("GET", "/items", nil))
+//	weblinkstest.AssertValid(t, rec.LinkHeader())
+//	weblinkstest.AssertLink(t, rec.LinkHeader(), "next", "/items?page=2")
+package weblinkstest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/conslo/webLinks"
+)
+
+// TestingT is the part of testing.TB the assertions use, so that they can be
+// used with other test frameworks too.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertValid checks that header is a valid Link header field value, parsed
+// strictly, reporting why it isn't otherwise.
+func AssertValid(t TestingT, header string) bool {
+	t.Helper()
+	if _, err := webLinks.ParseStrict(header); err != nil {
+		t.Errorf("invalid Link header %q: %v", header, err)
+		return false
+	}
+	return true
+}
+
+// AssertLink checks that header has a link to uri with the relation type rel,
+// among any others. The target is compared once normalized, as
+// webLinks.Link.Matches does, and ignored if uri is "".
+func AssertLink(t TestingT, header, rel, uri string) bool {
+	t.Helper()
+	if !webLinks.Parse(header).ContainsMatch(webLinks.BuildLink(uri).Rel(rel).Link()) {
+		t.Errorf("no link to %q with rel %q in Link header %q", uri, rel, header)
+		return false
+	}
+	return true
+}
+
+// AssertNoLink checks that header has no link with the relation type rel.
+func AssertNoLink(t TestingT, header, rel string) bool {
+	t.Helper()
+	if link, ok := webLinks.Parse(header).First(rel); ok {
+		t.Errorf("unexpected link with rel %q in Link header %q: %s", rel, header, link)
+		return false
+	}
+	return true
+}
+
+// AssertMatch checks that header has a link that matches pattern, as
+// webLinks.Link.Matches does, having all of its params.
+func AssertMatch(t TestingT, header string, pattern webLinks.Link) bool {
+	t.Helper()
+	if !webLinks.Parse(header).ContainsMatch(pattern) {
+		t.Errorf("no link matching %s in Link header %q", pattern, header)
+		return false
+	}
+	return true
+}
+
+// A Recorder is an httptest.ResponseRecorder that also tells the links it
+// recorded.
+type Recorder struct {
+	*httptest.ResponseRecorder
+}
+
+// NewRecorder returns an initialized Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{httptest.NewRecorder()}
+}
+
+// Record serves req with h, returning what it wrote.
+func Record(h http.Handler, req *http.Request) *Recorder {
+	rec := NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// Links returns the links in the "Link" headers written so far, as
+// webLinks.ParseHeader parses them.
+func (r *Recorder) Links() webLinks.Links {
+	return webLinks.ParseHeader(r.Result().Header)
+}
+
+// LinkHeader returns the "Link" headers written so far, joined into a single
+// field value, for the assertions.
+func (r *Recorder) LinkHeader() string {
+	return strings.Join(r.Result().Header.Values("Link"), ", ")
+}
@@ -0,0 +1,71 @@
+package weblinkstest_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conslo/webLinks"
+	"github.com/conslo/webLinks/weblinkstest"
+)
+
+// fakeT records the failures it is told about.
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+const header = `</items?page=2>; rel=next; per_page=50, </items?page=9>; rel=last`
+
+var assertTests = []struct {
+	assert func(weblinkstest.TestingT) bool
+	ok     bool
+}{
+	{func(t weblinkstest.TestingT) bool { return weblinkstest.AssertValid(t, header) }, true},
+	{func(t weblinkstest.TestingT) bool { return weblinkstest.AssertValid(t, `</a>; rel=next; title="x`) }, false},
+	{func(t weblinkstest.TestingT) bool { return weblinkstest.AssertLink(t, header, "next", "/items?page=2") }, true},
+	{func(t weblinkstest.TestingT) bool { return weblinkstest.AssertLink(t, header, "LAST", "") }, true},
+	{func(t weblinkstest.TestingT) bool { return weblinkstest.AssertLink(t, header, "next", "/items?page=9") }, false},
+	{func(t weblinkstest.TestingT) bool { return weblinkstest.AssertLink(t, header, "prev", "") }, false},
+	{func(t weblinkstest.TestingT) bool { return weblinkstest.AssertNoLink(t, header, "prev") }, true},
+	{func(t weblinkstest.TestingT) bool { return weblinkstest.AssertNoLink(t, header, "next") }, false},
+	{func(t weblinkstest.TestingT) bool {
+		return weblinkstest.AssertMatch(t, header, webLinks.BuildLink("").Rel("next").Param("per_page", "50").Link())
+	}, true},
+	{func(t weblinkstest.TestingT) bool {
+		return weblinkstest.AssertMatch(t, header, webLinks.BuildLink("").Rel("last").Param("per_page", "50").Link())
+	}, false},
+}
+
+func TestAssertions(t *testing.T) {
+	t.Parallel()
+	for i, test := range assertTests {
+		fake := &fakeT{}
+		if ok := test.assert(fake); ok != test.ok || (len(fake.errors) == 0) != test.ok {
+			t.Fatalf("Mismatch for assertion %d, got %t and %q\n", i, ok, fake.errors)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", `</items?page=2>; rel=next`)
+		w.Header().Add("Link", `</items?page=9>; rel=last`)
+		w.WriteHeader(http.StatusOK)
+	})
+	rec := weblinkstest.Record(handler, httptest.NewRequest("GET", "/items", nil))
+	if links := rec.Links(); len(links) != 2 || links[1].URI != "/items?page=9" {
+		t.Fatalf("Got %v\n", links)
+	}
+	if rec.LinkHeader() != `</items?page=2>; rel=next, </items?page=9>; rel=last` {
+		t.Fatalf("Got %q\n", rec.LinkHeader())
+	}
+	weblinkstest.AssertLink(t, rec.LinkHeader(), "next", "/items?page=2")
+}